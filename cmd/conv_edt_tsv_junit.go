@@ -1,19 +1,20 @@
 package main
 
 import (
-	"encoding/xml"
-	"encoding/csv"
+	"context"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
 
-	"github.com/azheval/conv_edt_tsv_junit/pkg/logging"
 	"github.com/azheval/conv_edt_tsv_junit/pkg/config"
+	"github.com/azheval/conv_edt_tsv_junit/pkg/converter"
+	"github.com/azheval/conv_edt_tsv_junit/pkg/discovery"
+	"github.com/azheval/conv_edt_tsv_junit/pkg/logging"
 )
 
 var (
@@ -21,114 +22,14 @@ var (
 	build   = ""
 )
 
-type ErrorRecord struct {
-	Date        string `xml:"date,attr"`
-	Priority    string `xml:"priority,attr"`
-	CheckType   string `xml:"checkType,attr"`
-	Project     string `xml:"project,attr"`
-	Standard    string `xml:"standard,attr"`
-	ErrorModule string `xml:"errorModule,attr"`
-	ErrorLine   string `xml:"errorLine,attr"`
-	ErrorText   string `xml:"errorText,attr"`
-}
-
-type TestSuites struct {
-	XMLName   xml.Name  `xml:"testsuites"`
-	Time      string    `xml:"time,attr"`
-	Tests     int       `xml:"tests,attr"`
-	Errors    int       `xml:"errors,attr"`
-	Failures  int       `xml:"failures,attr"`
-	TestSuite []TestSuite `xml:"testsuite"`
-}
-
-type TestSuite struct {
-	XMLName    xml.Name   `xml:"testsuite"`
-	Name       string     `xml:"name,attr"`
-	Timestamp  string     `xml:"timestamp,attr"`
-	Time       string     `xml:"time,attr"`
-	Tests      int        `xml:"tests,attr"`
-	Errors     int        `xml:"errors,attr"`
-	Failures   int        `xml:"failures,attr"`
-	Skipped    int        `xml:"skipped,attr"`
-	Properties []Property `xml:"properties>property"`
-	TestCases  []TestCase `xml:"testcase"`
-}
+// stdio is the -i and -o value for reading stdin and writing stdout.
+const stdio = "-"
 
-type Property struct {
-	Name  string `xml:"name,attr"`
-	Value string `xml:"value,attr"`
-}
-
-type TestCase struct {
-	XMLName   xml.Name  `xml:"testcase"`
-	ClassName string    `xml:"classname,attr"`
-	Name      string    `xml:"name,attr"`
-	Time      string    `xml:"time,attr"`
-	Failures  []Failure `xml:"failure"`
-}
-
-type Failure struct {
-	Message string `xml:"message,attr"`
-	Type    string `xml:"type,attr"`
-	Text    string `xml:",chardata"`
-}
-
-
-
-func getTestSuiteByName(testSuites TestSuites, testSuiteTimestamp string, fileName string, recordName string, logger slog.Logger) (TestSuite, int) {
-	if len(testSuites.TestSuite) == 0 {
-		ts := TestSuite{
-			Name:       fileName + "_" + recordName,
-			Timestamp:  testSuiteTimestamp,
-			Time:       "0",
-			Tests:      0,
-			Errors:     0,
-			Failures:   0,
-			Skipped:    0,
-			Properties: []Property{},
-			TestCases:  []TestCase{},
-		}
-		logger.Debug("new test suite", "name", fileName+"_"+recordName)
-		return ts, -1
-	} else {
-		for index, ts := range testSuites.TestSuite {
-			if ts.Name == fileName+"_"+recordName {
-				logger.Debug("found test suite", "name", fileName+"_"+recordName)
-				return ts, index
-			}
-		}
-		ts := TestSuite{
-			Name:       fileName + "_" + recordName,
-			Timestamp:  testSuiteTimestamp,
-			Time:       "0",
-			Tests:      0,
-			Errors:     0,
-			Failures:   0,
-			Skipped:    0,
-			Properties: []Property{},
-			TestCases:  []TestCase{},
-		}
-		logger.Debug("created test suite", "name", fileName+"_"+recordName)
-		return ts, -1
-	}
-}
+// validateConfigCommand checks the settings and exits without converting.
+const validateConfigCommand = "validate-config"
 
-func getTestCaseByName(testSuite TestSuite, testCaseName string, logger slog.Logger) (TestCase, int) {
-	for index, tc := range testSuite.TestCases {
-		if tc.Name == testCaseName {
-			logger.Debug("found test case", "name", testCaseName)
-			return tc, index
-		}
-	}
-	tc := TestCase{
-		ClassName: "",
-		Name:      testCaseName,
-		Time:      fmt.Sprintf("%f", 0.01),
-		Failures:  []Failure{},
-	}
-	logger.Debug("created test case", "name", testCaseName)
-	return tc, -1
-}
+// schemaCommand prints the JSON Schema of the settings file.
+const schemaCommand = "schema"
 
 func main() {
 	workspace, _ := os.Getwd()
@@ -139,7 +40,21 @@ func main() {
 	flag.StringVar(&settingsFilePath, "settings_file", "config.json", "Путь к файлу настроек проекта")
 	versionFlag := flag.Bool("version", false, "version number and exit")
 	debugFlag := flag.Bool("debug", false, "show debug messages")
-	flag.Parse()
+	profileFlag := flag.String("profile", "", "profile of the settings file")
+	strictFlag := flag.Bool("strict", false, "reject unknown settings, missing folders and files and malformed values")
+	settingsFlags := config.RegisterFlags(flag.CommandLine)
+
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == schemaCommand {
+		os.Stdout.Write(config.Schema)
+		os.Exit(0)
+	}
+
+	validateConfig := len(args) > 0 && args[0] == validateConfigCommand
+	if validateConfig {
+		args = args[1:]
+	}
+	flag.CommandLine.Parse(args)
 
 	if *versionFlag {
 		fmt.Printf("Version: %s\n", version)
@@ -147,250 +62,417 @@ func main() {
 		os.Exit(0)
 	}
 
-	configApp := loadConfigFromFile(settingsFilePath)
-	
-	logger := logging.CreateLogger(filepath.Join(workspace, configApp.OutputFileFolder, "edt_validator.log"), debugFlag)
+	settingsFileRequired := isFlagSet("settings_file")
+	if envSettingsFile, found := os.LookupEnv(config.EnvPrefix + "SETTINGS_FILE"); found && !settingsFileRequired {
+		settingsFilePath = envSettingsFile
+		settingsFileRequired = true
+	}
 
-	logger.Info("start application", "version", version, "build", build)
+	profile := *profileFlag
+	if envProfile, found := os.LookupEnv(config.EnvPrefix + "PROFILE"); found && !isFlagSet("profile") {
+		profile = envProfile
+	}
 
-	files, err := os.ReadDir(filepath.Join(workspace, configApp.InputFileFolder))
+	_, err := os.Stat(settingsFilePath)
+	settingsFileUsed := err == nil || settingsFileRequired
+	if profile != "" && !settingsFileUsed {
+		fmt.Fprintf(os.Stderr, "profile %s requires the settings file %s\n", profile, settingsFilePath)
+		os.Exit(2)
+	}
+
+	var problems []config.Problem
+	if settingsFileUsed && (validateConfig || *strictFlag) {
+		problems = config.CheckFile(settingsFilePath, profile)
+		if config.Fatal(problems) {
+			exitWithProblems(problems)
+		}
+	}
+
+	configApp, err := loadConfig(settingsFilePath, settingsFileUsed, profile, settingsFlags)
 	if err != nil {
-		logger.Error("failed reading input file folder", "error", err.Error())
-		return
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
 	}
 
-	isParentErrors := bool(configApp.SkipErrorsFile != "")
-	var parentErrors [][]string
-	var parentErrorsKeys map[string]struct{}
-	if isParentErrors {
-		parentErrors, parentErrorsKeys, err = readTSVFile(filepath.Join(workspace, configApp.InputFileFolder, configApp.SkipErrorsFile), logger)
-		if err != nil {
-			logger.Error("failed reading parent errors file", "error", err.Error())
-			return
+	// Folders given by flags or environment variables are relative to the working directory.
+	configApp.InputFileFolder = config.ResolvePath(workspace, configApp.InputFileFolder)
+	configApp.OutputFileFolder = config.ResolvePath(workspace, configApp.OutputFileFolder)
+	if configApp.SourceDir != "" {
+		configApp.SourceDir = config.ResolvePath(workspace, configApp.SourceDir)
+	}
+	if configApp.DiffFile != "" {
+		configApp.DiffFile = config.ResolvePath(workspace, configApp.DiffFile)
+	}
+	if configApp.OwnersFile != "" {
+		configApp.OwnersFile = config.ResolvePath(workspace, configApp.OwnersFile)
+	}
+	// The skip errors file of the settings file stays relative to the input file folder.
+	_, skipErrorsFileEnv := os.LookupEnv(config.EnvPrefix + "SKIP_ERRORS_FILE")
+	if configApp.SkipErrorsFile != "" && (isFlagSet("skip-errors-file") || skipErrorsFileEnv) {
+		configApp.SkipErrorsFile = config.ResolvePath(workspace, configApp.SkipErrorsFile)
+	}
+
+	if validateConfig || *strictFlag {
+		problems = append(problems, configApp.Check()...)
+		_, pipelineProblems := createPipeline(configApp)
+		problems = append(problems, pipelineProblems...)
+		if len(problems) > 0 {
+			exitWithProblems(problems)
+		}
+		if validateConfig {
+			fmt.Println("config is valid")
+			os.Exit(0)
 		}
+	}
 
-		parentFileExtension := filepath.Ext(configApp.SkipErrorsFile)
-		parentFileName := strings.TrimSuffix(configApp.SkipErrorsFile, parentFileExtension)
-		createNewTestSuites(parentErrors, logger, false, parentErrorsKeys, testSuiteTimestamp, parentFileName, configApp.OutputFileFolder, configApp.SkipObjects, configApp.SkipCategories, configApp.SkipSignificanceCcategories, configApp.SkipErrorText)
+	if problems := configApp.CheckStdout(); len(problems) > 0 {
+		exitWithProblems(problems)
 	}
 
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".tsv") {
-			fileName := strings.TrimSuffix(file.Name(), ".tsv")
+	readStdin := configApp.InputFileFolder == stdio
+	writeStdout := configApp.OutputFileFolder == stdio
 
-			logger.Debug("start processing file", "file", file.Name())
+	var logger *slog.Logger
+	if writeStdout {
+		logger = logging.CreateStderrLogger(debugFlag)
+	} else {
+		logger = logging.CreateLogger(filepath.Join(configApp.OutputFileFolder, "edt_validator.log"), debugFlag)
+	}
 
-			tsvFile, err := os.Open(filepath.Join(configApp.InputFileFolder, file.Name()))
-			if err != nil {
-				logger.Error("failed opening tsv file", "file", file.Name(), "error", err.Error())
-				panic(err)
-			}
-			defer tsvFile.Close()
-
-			reader := csv.NewReader(tsvFile)
-			reader.Comma = '\t'
-			reader.LazyQuotes = true
-			reader.FieldsPerRecord = -1
-			
-			records, err := reader.ReadAll()
+	logger.Info("start application", "version", version, "build", build)
+
+	ctx := context.Background()
+
+	p, problems := createPipeline(configApp)
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			logger.Error("failed creating pipeline", "path", problem.Path, "error", problem.Message)
+		}
+		os.Exit(1)
+	}
+
+	opts := converter.Options{
+		Timestamp:                  testSuiteTimestamp,
+		SkipObjects:                configApp.SkipObjects,
+		SkipCategories:             configApp.SkipCategories,
+		SkipSignificanceCategories: configApp.SkipSignificanceCategories,
+		SkipErrorText:              configApp.SkipErrorText,
+		Filters:                    p.filters,
+		Projects:                   p.projects,
+		SourceDir:                  configApp.SourceDir,
+		Blame:                      configApp.Blame,
+		Severities:                 configApp.Severities,
+		Owners:                     p.owners,
+		GroupBy:                    configApp.GroupBy,
+		Logger:                     logger,
+	}
+	var output converter.Output = converter.DirOutput(configApp.OutputFileFolder)
+	if writeStdout {
+		output = converter.WriterOutput{W: os.Stdout}
+	}
+
+	if configApp.SkipErrorsFile != "" {
+		skipErrorsFolder := configApp.InputFileFolder
+		if readStdin {
+			skipErrorsFolder = workspace
+		}
+		parentErrors, _, err := readTSVFile(config.ResolvePath(skipErrorsFolder, configApp.SkipErrorsFile), logger)
+		if err != nil {
+			logger.Error("failed reading parent errors file", "error", err.Error())
+			return
+		}
+
+		parentFileName := filepath.Base(configApp.SkipErrorsFile)
+		parentOpts := opts
+		parentOpts.Name = strings.TrimSuffix(parentFileName, filepath.Ext(parentFileName))
+		parentSinks, parentProjects := baselineSinks(p.sinks, p.projects)
+		parentOpts.Projects = parentProjects
+		if !writeStdout && configApp.MergedReport == "" {
+			err = converter.Process(ctx, parentErrors, output, parentSinks, parentOpts)
 			if err != nil {
-				logger.Error("failed reading tsv file", "file", file.Name(), "error", err.Error())
+				logger.Error("failed converting parent errors file", "error", err.Error())
 				panic(err)
 			}
-
-			createNewTestSuites(records, logger, isParentErrors, parentErrorsKeys, testSuiteTimestamp, fileName, configApp.OutputFileFolder, configApp.SkipObjects, configApp.SkipCategories, configApp.SkipSignificanceCcategories, configApp.SkipErrorText)
 		}
+
+		opts.Baseline = converter.NewBaseline(parentErrors, configApp.SkipErrorsLineTolerance)
 	}
-	logger.Info("end application")
-}
+	opts.Changes = p.changes
 
-func loadConfigFromFile(settingsFilePath string) *config.AppConfig {
-	configApp := config.NewAppConfig()
-	config.LoadConfig(configApp, settingsFilePath)
-	return configApp
-}
+	if readStdin {
+		logger.Debug("start processing stdin")
 
-func createNewTestSuites(records [][]string, logger *slog.Logger, isParentErrors bool, parentErrorsKeys map[string]struct{}, testSuiteTimestamp string, fileName string, outputFileFolder string, skipObjects []string, skipCategories []string, skipSignificanceCategories []string, skipErrorText []string) {
+		stdinOpts := opts
+		stdinOpts.Name = "stdin"
+		if configApp.MergedReport != "" {
+			stdinOpts.Name = configApp.MergedReport
+		}
+		err := converter.Run(ctx, p.source, os.Stdin, output, p.sinks, stdinOpts)
+		if err != nil {
+			logger.Error("failed converting stdin", "error", err.Error())
+			os.Exit(1)
+		}
+		logger.Info("end application")
+		return
+	}
 
-	testSuites := TestSuites{
-		Time:      "0",
-		Tests:     0,
-		Errors:    0,
-		Failures:  0,
-		TestSuite: []TestSuite{},
+	files, err := discovery.Find(configApp.InputFileFolder, configApp.Include, configApp.Exclude)
+	if err != nil {
+		logger.Error("failed reading input file folder", "error", err.Error())
+		return
 	}
 
-	for _, record := range records {
-		if recordInSkipList(record, skipObjects, skipCategories, skipSignificanceCategories, skipErrorText) {
-			logger.Debug("record in skip list", "record", record)
+	// The skip errors file is not converted when it is one of the input files.
+	var skipErrorsFile string
+	if configApp.SkipErrorsFile != "" {
+		relPath, err := filepath.Rel(configApp.InputFileFolder, config.ResolvePath(configApp.InputFileFolder, configApp.SkipErrorsFile))
+		if err == nil {
+			skipErrorsFile = filepath.ToSlash(relPath)
+		}
+	}
+	var merged []converter.ErrorRecord
+	for _, file := range files {
+		if file == skipErrorsFile {
 			continue
 		}
+		logger.Debug("start processing file", "file", file)
 
-		if isParentErrors {
-			if recordInSkipErrorsList(record, parentErrorsKeys) {
-				logger.Debug("record in skip errors list", "record", record)
-				continue
+		// The reports mirror the folders of the input files.
+		name := strings.TrimSuffix(file, path.Ext(file))
+		records, err := readFile(ctx, filepath.Join(configApp.InputFileFolder, filepath.FromSlash(file)), p.source)
+		if err != nil {
+			logger.Error("failed reading tsv file", "file", file, "error", err.Error())
+			panic(err)
+		}
+
+		if configApp.MergedReport != "" {
+			for i := range records {
+				records[i].Source = name
 			}
+			merged = append(merged, records...)
+			continue
 		}
-		testSuite, indexTestSuite := getTestSuiteByName(testSuites, testSuiteTimestamp, fileName, record[1]+"_"+record[2], *logger)
-		testCase, indexTestCase := getTestCaseByName(testSuite, record[5], *logger)
-
-		failure := Failure{}
-		failure.Type = record[2]
-		failure.Message = record[1] + "; " + record[2] + "; " + record[4]
-		failure.Text = record[5] + "; " + record[6] + "; " + record[7]
-		logger.Debug("added failure", "type", failure.Type, "message", failure.Message, "text", failure.Text)
-		testCase.Failures = append(testCase.Failures, failure)
-
-		if indexTestCase == -1 {
-			testSuite.TestCases = append(testSuite.TestCases, testCase)
-		} else {
-			testSuite.TestCases[indexTestCase] = testCase
+
+		fileOpts := opts
+		fileOpts.Name = name
+		err = converter.Process(ctx, records, output, p.sinks, fileOpts)
+		if err != nil {
+			logger.Error("failed converting tsv file", "file", file, "error", err.Error())
+			panic(err)
 		}
-		testSuite.Tests++
-		testSuite.Failures++
+	}
 
-		if indexTestSuite == -1 {
-			testSuites.TestSuite = append(testSuites.TestSuite, testSuite)
-		} else {
-			testSuites.TestSuite[indexTestSuite] = testSuite
+	if configApp.MergedReport != "" {
+		mergedOpts := opts
+		mergedOpts.Name = configApp.MergedReport
+		err = converter.Process(ctx, merged, output, p.sinks, mergedOpts)
+		if err != nil {
+			logger.Error("failed converting merged report", "name", configApp.MergedReport, "error", err.Error())
+			panic(err)
 		}
-		testSuites.Tests++
-		testSuites.Failures++
-	}
-
-	for index_ts, ts := range testSuites.TestSuite {
-		var newTestCases []TestCase
-		for _, tc := range ts.TestCases {
-			if len(tc.Failures) > 1 {
-				for i, f := range tc.Failures {
-					newTestCase := TestCase{
-						ClassName: tc.ClassName + "_unique_" + strconv.Itoa(i),
-						Name:      tc.Name,
-						Time:      tc.Time,
-						Failures:  []Failure{f},
-					}
-					newTestCases = append(newTestCases, newTestCase)
-					logger.Debug("added new test case", "name", newTestCase.Name, "class", newTestCase.ClassName)
-				}
-			} else {
-				newTestCases = append(newTestCases, tc)
-			}
+	}
+	logger.Info("end application")
+}
 
+// loadConfig applies the settings file with the profile, CONV_EDT_* environment
+// variables and command line flags in order of increasing precedence. The settings
+// file may be absent unless it was given explicitly.
+func loadConfig(settingsFilePath string, settingsFileUsed bool, profile string, settingsFlags *config.Flags) (*config.AppConfig, error) {
+	configApp := config.NewAppConfig()
+	configApp.Profile = profile
+	if settingsFileUsed {
+		if err := config.LoadConfig(configApp, settingsFilePath); err != nil {
+			return nil, err
 		}
-		testSuites.TestSuite[index_ts].TestCases = newTestCases
 	}
 
-	writeXMLData(logger, testSuites, fileName, outputFileFolder)
+	if err := configApp.LoadEnv(os.Environ()); err != nil {
+		return nil, err
+	}
+	if err := settingsFlags.Apply(configApp); err != nil {
+		return nil, err
+	}
+	return configApp, nil
 }
 
-func writeXMLData(logger *slog.Logger, testSuites TestSuites, fileName string, outputFileFolder string) {
-	xmlData, err := xml.MarshalIndent(testSuites, "", "    ")
-	if err != nil {
-		logger.Error("failed marshaling xml", "error", err.Error())
-		panic(err)
-	}
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
 
-	xmlFile, err := os.Create(filepath.Join(outputFileFolder, fileName+".xml"))
-	if err != nil {
-		logger.Error("failed creating xml", "error", err.Error())
-		panic(err)
+// exitWithProblems prints the config problems and exits with code 1.
+func exitWithProblems(problems []config.Problem) {
+	for _, problem := range problems {
+		fmt.Fprintln(os.Stderr, problem)
 	}
-	defer xmlFile.Close()
+	os.Exit(1)
+}
 
-	_, err = xmlFile.Write([]byte(xml.Header))
+// pipeline holds the plugins created from the settings.
+type pipeline struct {
+	source   converter.Source
+	filters  []converter.Filter
+	sinks    []converter.Sink
+	projects map[string]converter.ProjectOptions
+	changes  *converter.Changes
+	owners   *converter.Owners
+}
+
+// createPipeline creates the configured plugins, every plugin which cannot
+// be created is reported as a problem.
+func createPipeline(configApp *config.AppConfig) (pipeline, []config.Problem) {
+	var p pipeline
+	var problems []config.Problem
+
+	source, err := converter.NewSource(configApp.Source.Type, configApp.Source.Options)
 	if err != nil {
-		logger.Error("failed writing header", "error", err.Error())
-		panic(err)
+		problems = append(problems, config.Problem{Path: "$.source", Message: err.Error()})
 	}
+	p.source = source
 
-	_, err = xmlFile.Write(xmlData)
-	if err != nil {
-		logger.Error("failed writing xml data", "error", err.Error())
-		panic(err)
+	var filterProblems, sinkProblems []config.Problem
+	p.filters, filterProblems = createFilters("$.filters", configApp.Filters)
+	p.sinks, sinkProblems = createSinks("$.sinks", configApp.Sinks)
+	problems = append(append(problems, filterProblems...), sinkProblems...)
+
+	if configApp.GroupBy != "" {
+		if _, err := converter.RecordField(converter.ErrorRecord{}, configApp.GroupBy); err != nil {
+			problems = append(problems, config.Problem{Path: "$.group_by", Message: err.Error()})
+		}
 	}
-}
 
-func recordInSkipErrorsList(record []string, parentErrorsKeys map[string]struct{}) bool {
-	newSlice := make([]string, len(record))
-	copy(newSlice, record)
-	
-	key := strings.Join(append(newSlice[1:2], newSlice[3:]...), "\t")
-	if _, found := parentErrorsKeys[key]; !found {
-		return false
+	for significance, severity := range configApp.Severities {
+		if err := converter.CheckSeverity(severity); err != nil {
+			problems = append(problems, config.Problem{Path: "$.severities." + significance, Message: err.Error()})
+		}
 	}
-	return true
-}
 
-func recordInSkipList(record []string, skipObjects []string, skipCategories []string, skipSignificanceCategories []string, skipErrorText []string) bool {
-	return recordInSkipObject(record, skipObjects) || recordInSkipCategory(record, skipCategories) || recordInSkipSignificanteCategories(record, skipSignificanceCategories) || recordInSkipErrorText(record, skipErrorText)
-}
+	if configApp.OwnersFile != "" {
+		owners, err := converter.ReadOwners(configApp.OwnersFile)
+		if err != nil {
+			problems = append(problems, config.Problem{Path: "$.owners_file", Message: err.Error()})
+		}
+		p.owners = owners
+	}
 
-func recordInSkipCategory(record []string, skipCategories []string) bool {
-	for _, skip := range skipCategories {
-		if skip == record[2] {
-			return true
+	if configApp.Since != "" || configApp.DiffFile != "" {
+		changesPath := "$.since"
+		if configApp.DiffFile != "" {
+			changesPath = "$.diff_file"
+		}
+		if configApp.Since != "" && configApp.DiffFile != "" {
+			problems = append(problems, config.Problem{Path: changesPath, Message: "since and diff_file cannot be used together"})
+		} else if configApp.SourceDir == "" {
+			// Without the sources the records have no paths and every record
+			// would be skipped as unchanged.
+			problems = append(problems, config.Problem{Path: changesPath, Message: "source_dir is required"})
+		} else {
+			changes, err := converter.ReadChanges(configApp.DiffFile, configApp.SourceDir, configApp.Since)
+			if err != nil {
+				problems = append(problems, config.Problem{Path: changesPath, Message: err.Error()})
+			}
+			p.changes = changes
 		}
 	}
-	return false
-}
 
-func recordInSkipObject(record []string, skipObjects []string) bool {
-	for _, skip := range skipObjects {
-		if skip == record[5] {
-			return true
+	if len(configApp.Projects) > 0 {
+		p.projects = make(map[string]converter.ProjectOptions)
+	}
+	for name, projectConfig := range configApp.Projects {
+		projectPath := "$.projects." + name
+		project := converter.ProjectOptions{
+			SkipObjects:                projectConfig.SkipObjects,
+			SkipCategories:             projectConfig.SkipCategories,
+			SkipSignificanceCategories: projectConfig.SkipSignificanceCategories,
+			SkipErrorText:              projectConfig.SkipErrorText,
+			GroupBy:                    projectConfig.GroupBy,
 		}
+		if projectConfig.GroupBy != "" {
+			if _, err := converter.RecordField(converter.ErrorRecord{}, projectConfig.GroupBy); err != nil {
+				problems = append(problems, config.Problem{Path: projectPath + ".group_by", Message: err.Error()})
+			}
+		}
+		project.Filters, filterProblems = createFilters(projectPath+".filters", projectConfig.Filters)
+		project.Sinks, sinkProblems = createSinks(projectPath+".sinks", projectConfig.Sinks)
+		problems = append(append(problems, filterProblems...), sinkProblems...)
+		if projectConfig.OutputFileFolder != "" {
+			project.Output = converter.DirOutput(projectConfig.OutputFileFolder)
+		}
+		p.projects[name] = project
+	}
+	return p, problems
+}
 
-		if strings.Contains(record[5], skip) {
-            return true
-        }
+// baselineSinks returns the sinks of the skip errors file report,
+// see converter.BaselineSinks.
+func baselineSinks(sinks []converter.Sink, projects map[string]converter.ProjectOptions) ([]converter.Sink, map[string]converter.ProjectOptions) {
+	if projects == nil {
+		return converter.BaselineSinks(sinks), nil
 	}
-	return false
+	baselineProjects := make(map[string]converter.ProjectOptions, len(projects))
+	for name, project := range projects {
+		project.Sinks = converter.BaselineSinks(project.Sinks)
+		baselineProjects[name] = project
+	}
+	return converter.BaselineSinks(sinks), baselineProjects
 }
 
-func recordInSkipSignificanteCategories(record []string, skipSignificanceCategories []string) bool {
-	for _, skip := range skipSignificanceCategories {
-		if skip == fmt.Sprintf("%s_%s", record[1], record[2]) {
-			return true
+func createFilters(path string, filterConfigs []config.PluginConfig) ([]converter.Filter, []config.Problem) {
+	var filters []converter.Filter
+	var problems []config.Problem
+	for i, filterConfig := range filterConfigs {
+		filter, err := converter.NewFilter(filterConfig.Type, filterConfig.Options)
+		if err != nil {
+			problems = append(problems, config.Problem{Path: fmt.Sprintf("%s[%d]", path, i), Message: err.Error()})
+			continue
 		}
+		filters = append(filters, filter)
 	}
-	return false
+	return filters, problems
 }
 
-func recordInSkipErrorText(record []string, skipErrorText []string) bool {
-	for _, skip := range skipErrorText {
-		if skip == record[7] {
-			return true
+// createSinks returns nil when no sinks are configured.
+func createSinks(path string, sinkConfigs []config.PluginConfig) ([]converter.Sink, []config.Problem) {
+	var sinks []converter.Sink
+	var problems []config.Problem
+	for i, sinkConfig := range sinkConfigs {
+		sink, err := converter.NewSink(sinkConfig.Type, sinkConfig.Options)
+		if err != nil {
+			problems = append(problems, config.Problem{Path: fmt.Sprintf("%s[%d]", path, i), Message: err.Error()})
+			continue
 		}
+		sinks = append(sinks, sink)
 	}
-	return false
+	return sinks, problems
 }
 
-func readTSVFile(filePath string, logger *slog.Logger) ([][]string, map[string]struct{}, error) {
+func readFile(ctx context.Context, filePath string, source converter.Source) ([]converter.ErrorRecord, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	defer file.Close()
-	
-	reader := csv.NewReader(file)
-	reader.Comma = '\t'
-	reader.LazyQuotes = true
-	reader.FieldsPerRecord = -1
 
-	records, err := reader.ReadAll()
+	return source.Read(ctx, file)
+}
+
+func readTSVFile(filePath string, logger *slog.Logger) ([]converter.ErrorRecord, map[string]struct{}, error) {
+	file, err := os.Open(filePath)
 	if err != nil {
-		logger.Error("failed reading parent tsv", "error", err.Error())
-		panic(err)
+		return nil, nil, err
 	}
+	defer file.Close()
 
-	lines := make(map[string]struct{})
-	for _, record := range records {
-		newSlice := make([]string, len(record))
-		copy(newSlice, record)
-		key := strings.Join(append(newSlice[1:2], newSlice[3:]...), "\t")
-		lines[key] = struct{}{}
+	records, err := converter.ReadTSV(file)
+	if err != nil {
+		logger.Error("failed reading tsv", "file", filePath, "error", err.Error())
+		return nil, nil, err
 	}
 
-	return records, lines, nil
+	return records, converter.BaselineKeys(records), nil
 }
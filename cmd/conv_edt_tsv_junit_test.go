@@ -1,280 +1,16 @@
 package main
 
 import (
-	"encoding/xml"
-	"os"
+	"context"
 	"log/slog"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
-	"github.com/stretchr/testify/assert"
-)
-
-func TestGetTestSuiteByName_WhenTestSuitesIsEmpty(t *testing.T) {
-	testSuites := TestSuites{
-		XMLName:   xml.Name{},
-		Time:      "0",
-		Tests:     0,
-		Errors:    0,
-		Failures:  0,
-		TestSuite: []TestSuite{},
-	}
-	testSuiteTimestamp := "2023-01-01T12:00:00"
-	fileName := "test_file"
-	recordName := "test_record"
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-
-	expectedTestSuite := TestSuite{
-		Name:       fileName + "_" + recordName,
-		Timestamp:  testSuiteTimestamp,
-		Time:       "0",
-		Tests:      0,
-		Errors:     0,
-		Failures:   0,
-		Skipped:    0,
-		Properties: []Property{},
-		TestCases:  []TestCase{},
-	}
-	expectedIndex := -1
-
-	actualTestSuite, actualIndex := getTestSuiteByName(testSuites, testSuiteTimestamp, fileName, recordName, *logger)
-
-	if !reflect.DeepEqual(actualTestSuite, expectedTestSuite) {
-		t.Errorf("expected test suite: %v, got: %v", expectedTestSuite, actualTestSuite)
-	}
-	if actualIndex != expectedIndex {
-		t.Errorf("expected index: %d, got: %d", expectedIndex, actualIndex)
-	}
-}
-
-func TestGetTestSuiteByName_ExistingTestSuite(t *testing.T) {
-	testSuites := TestSuites{
-		Time:      "0",
-		Tests:     0,
-		Errors:    0,
-		Failures:  0,
-		TestSuite: []TestSuite{
-			{
-				Name:      "filename_recordname",
-				Timestamp: "2022-01-01T12:00:00",
-				Time:      "0",
-				Tests:     10,
-				Errors:    0,
-				Failures:  0,
-				Skipped:   0,
-				Properties: []Property{},
-				TestCases:  []TestCase{},
-			},
-		},
-	}
-	testSuiteTimestamp := "2022-01-01T12:00:00"
-	fileName := "filename"
-	recordName := "recordname"
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-
-	resultTestSuite, _ := getTestSuiteByName(testSuites, testSuiteTimestamp, fileName, recordName, *logger)
-
-	expectedTestSuite := TestSuite{
-		Name:      "filename_recordname",
-		Timestamp: "2022-01-01T12:00:00",
-		Time:      "0",
-		Tests:     10,
-		Errors:    0,
-		Failures:  0,
-		Skipped:   0,
-		Properties: []Property{},
-		TestCases:  []TestCase{},
-	}
-	assert.Equal(t, expectedTestSuite, resultTestSuite, "gettestsuitebyname should return an existing test suite")
-}
-
-func TestGetTestSuiteByName_NotExistingTestSuite(t *testing.T) {
-	testSuites := TestSuites{
-		Time:      "0",
-		Tests:     0,
-		Errors:    0,
-		Failures:  0,
-		TestSuite: []TestSuite{
-			{
-				Name:      "filename_recordname_1",
-				Timestamp: "2022-01-01T12:00:00",
-				Time:      "0",
-				Tests:     10,
-				Errors:    0,
-				Failures:  0,
-				Skipped:   0,
-				Properties: []Property{},
-				TestCases:  []TestCase{},
-			},
-		},
-	}
-	testSuiteTimestamp := "2022-01-01T12:00:00"
-	fileName := "filename"
-	recordName := "recordname"
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-
-	resultTestSuite, _ := getTestSuiteByName(testSuites, testSuiteTimestamp, fileName, recordName, *logger)
 
-	expectedTestSuite := TestSuite{
-		Name:      "filename_recordname",
-		Timestamp: "2022-01-01T12:00:00",
-		Time:      "0",
-		Tests:     0,
-		Errors:    0,
-		Failures:  0,
-		Skipped:   0,
-		Properties: []Property{},
-		TestCases:  []TestCase{},
-	}
-	assert.Equal(t, expectedTestSuite, resultTestSuite, "gettestsuitebyname should return an existing test suite")
-}
-
-func TestGetTestCaseByName_ExistingTestCase(t *testing.T) {
-	testSuite := TestSuite{
-		TestCases: []TestCase{
-			{
-				Name: "TestCase1",
-			},
-			{
-				Name: "TestCase2",
-			},
-		},
-	}
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	testCaseName := "TestCase2"
-
-	testCase, index := getTestCaseByName(testSuite, testCaseName, *logger)
-
-	if index != 1 {
-		t.Errorf("Expected index 1, but got %d", index)
-	}
-	if testCase.Name != testCaseName {
-		t.Errorf("Expected testCaseName %s, but got %s", testCaseName, testCase.Name)
-	}
-}
-
-func TestGetTestCaseByName_CreatesNewTestCase_WhenNameDoesNotMatch(t *testing.T) {
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	testSuite := TestSuite{
-		TestCases: []TestCase{
-			{Name: "TestCase1"},
-			{Name: "TestCase2"},
-		},
-	}
-	testCaseName := "TestCase3"
-
-	testCase, _ := getTestCaseByName(testSuite, testCaseName, *logger)
-
-	if testCase.Name != testCaseName {
-		t.Errorf("Expected testCase.Name to be %s, but got %s", testCaseName, testCase.Name)
-	}
-}
-
-func TestGetTestCaseByName_EmptyTestCaseName(t *testing.T) {
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	testSuite := TestSuite{
-		TestCases: []TestCase{},
-	}
-	testCaseName := ""
-
-	testCase, index := getTestCaseByName(testSuite, testCaseName, *logger)
-
-	if index != -1 {
-		t.Errorf("Expected index to be -1, but got %d", index)
-	}
-	if testCase.Name != "" {
-		t.Errorf("Expected empty testCase.Name, but got %s", testCase.Name)
-	}
-}
-
-func TestRecordInSkipCategory(t *testing.T) {
-	skipCategories := []string{"Предупреждение"}
-
-    tests := []struct {
-        input  []string
-        output bool
-    }{
-		{input: []string{"Предупреждение", "Предупреждение", "Предупреждение"}, output: true},
-		{input: []string{"A1", "B2", "B2"}, output: false},
-    }
-
-    for _, tt := range tests {
-        result := recordInSkipCategory(tt.input, skipCategories)
-        if result != tt.output {
-            t.Errorf("recordInSkipCategory(%q) = %v, want %v", tt.input, result, tt.output)
-        }
-    }
-}
-
-func TestRecordInSkipObject(t *testing.T) {
-	skipObjects := []string{"Справочник.Номенклатура.МодульОбъекта", ".Удалить"}
-
-	tests := []struct {
-		input  []string
-		output bool
-	}{
-		{input: []string{"A1", "A1", "A1", "A1","A1", "A1"}, output: false},
-		{input: []string{"A1", "A1", "A1", "A1","A1", "Справочник.Номенклатура.МодульОбъекта"}, output: true},
-		{input: []string{"A1", "A1", "A1", "A1","A1", "Справочник.Удалить_Номенклатура.МодульОбъекта"}, output: true},
-		{input: []string{"A1", "A1", "A1", "A1","A1", "Справочник.УдалитьНоменклатура.МодульОбъекта"}, output: true},
-	}
-
-	for _, tt := range tests {
-		result := recordInSkipObject(tt.input, skipObjects)
-		if result != tt.output {
-			t.Errorf("recordInSkipObject(%q) = %v, want %v", tt.input, result, tt.output)
-		}
-	}
-}
-
-func TestRecordInSkipList(t *testing.T) {
-	skipCategories := []string{"Предупреждение"}
-	skipObjects := []string{"Справочник.Номенклатура.МодульОбъекта", ".Удалить"}
-	skipSignificanteCategories := []string{"Значительная_Переносимость"}
-	skipErrorText := []string{"Неподдерживаемый оператор [Web-клиент]"}
-
-	tests := []struct {
-		input  []string
-		output bool
-	}{
-		{input: []string{"A1", "A1", "A1", "A1","A1", "A1", "", ""}, output: false},
-		{input: []string{"A1", "A1", "A1", "A1","A1", "A1", "", "Неподдерживаемый оператор [Web-клиент]"}, output: true},
-		{input: []string{"A1", "A1", "Предупреждение", "A1","A1", "A1", "", ""}, output: true},
-		{input: []string{"A1", "Значительная", "Переносимость", "A1","A1", "A1", "", ""}, output: true},
-		{input: []string{"A1", "A1", "A1", "A1","A1", "Справочник.Номенклатура.МодульОбъекта", "", ""}, output: true},
-		{input: []string{"A1", "A1", "A1", "A1","A1", "Справочник.Удалить_Номенклатура.МодульОбъекта", "", ""}, output: true},
-		{input: []string{"A1", "A1", "A1", "A1","A1", "Справочник.УдалитьНоменклатура.МодульОбъекта", "", ""}, output: true},
-	}
-
-	for _, tt := range tests {
-		result := recordInSkipList(tt.input, skipObjects, skipCategories, skipSignificanteCategories, skipErrorText)
-		if result != tt.output {
-			t.Errorf("recordInSkipList(%q) = %v, want %v", tt.input, result, tt.output)
-		}
-	}
-}
-
-func TestRecordInSkipErrorsList(t *testing.T) {
-	record := []string{"A1", "A1", "A1", "A1","A1", "A1", "A1", "A1"}
-	key := strings.Join(append(record[1:2], record[3:]...), "\t")
-	parentErrorsKeys := make(map[string]struct{})
-	parentErrorsKeys[key] = struct{}{}
-
-	tests := []struct {
-		input  []string
-		output bool
-	}{
-		{input: []string{"A1", "A1", "A1", "A1","A1", "A1", "A1", "A1"}, output: true},
-		{input: []string{"A1", "A2", "A1", "A1","A1", "A1", "A1", "A1"}, output: false},
-	}
-
-	for _, tt := range tests {
-		result := recordInSkipErrorsList(tt.input, parentErrorsKeys)
-		if result != tt.output {
-			t.Errorf("recordInSkipErrorsList(%q) = %v, want %v", tt.input, result, tt.output)
-		}
-	}
-}
+	"github.com/azheval/conv_edt_tsv_junit/pkg/config"
+	"github.com/azheval/conv_edt_tsv_junit/pkg/converter"
+)
 
 func TestReadTSVFile_FileNotFound(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
@@ -336,10 +72,10 @@ func TestReadTSVFileWithLines(t *testing.T) {
 		t.Fatalf("readTSVFile should not return an error, but got: %v", err)
 	}
 
-	expectedRecords := [][]string{
-		{"2024-07-26T15:12:49+0300","Тривиальная","Стандарты кодирования","cf","com.e1c.v8codestyle.bsl:doc-comment-field-in-description-suggestion","ОбщийМодуль.WebAPI_Локализация.Модуль","строка 13","Возможно Поле указано в описании"},
-		{"2024-07-26T15:12:49+0300","Тривиальная","Стандарты кодирования","cf","com.e1c.v8codestyle.bsl:doc-comment-field-in-description-suggestion","ОбщийМодуль.WebAPI_Локализация.Модуль","строка 15","Возможно Поле указано в описании"},
-		{"2024-07-26T15:12:49+0300","Тривиальная","Стандарты кодирования","cf","com.e1c.v8codestyle.bsl:doc-comment-field-in-description-suggestion","ОбщийМодуль.WebAPI_Локализация.Модуль","строка 294","Возможно Поле указано в описании"},
+	expectedRecords := []converter.ErrorRecord{
+		converter.NewErrorRecord([]string{"2024-07-26T15:12:49+0300", "Тривиальная", "Стандарты кодирования", "cf", "com.e1c.v8codestyle.bsl:doc-comment-field-in-description-suggestion", "ОбщийМодуль.WebAPI_Локализация.Модуль", "строка 13", "Возможно Поле указано в описании"}),
+		converter.NewErrorRecord([]string{"2024-07-26T15:12:49+0300", "Тривиальная", "Стандарты кодирования", "cf", "com.e1c.v8codestyle.bsl:doc-comment-field-in-description-suggestion", "ОбщийМодуль.WebAPI_Локализация.Модуль", "строка 15", "Возможно Поле указано в описании"}),
+		converter.NewErrorRecord([]string{"2024-07-26T15:12:49+0300", "Тривиальная", "Стандарты кодирования", "cf", "com.e1c.v8codestyle.bsl:doc-comment-field-in-description-suggestion", "ОбщийМодуль.WebAPI_Локализация.Модуль", "строка 294", "Возможно Поле указано в описании"}),
 	}
 
 	if !reflect.DeepEqual(records, expectedRecords) {
@@ -352,3 +88,53 @@ func TestReadTSVFileWithLines(t *testing.T) {
 		}
 	}()
 }
+
+func TestCreatePipeline_ChangesRequireSourceDir(t *testing.T) {
+	configApp := config.NewAppConfig()
+	configApp.DiffFile = "changes.patch"
+
+	_, problems := createPipeline(configApp)
+
+	expected := []config.Problem{{Path: "$.diff_file", Message: "source_dir is required"}}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("expected %v, but got %v", expected, problems)
+	}
+
+	configApp.SourceDir = t.TempDir()
+	configApp.Since = "main"
+	_, problems = createPipeline(configApp)
+
+	expected = []config.Problem{{Path: "$.diff_file", Message: "since and diff_file cannot be used together"}}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("expected %v, but got %v", expected, problems)
+	}
+}
+
+func TestBaselineSinks_SkipAnnotations(t *testing.T) {
+	var annotations, xmlData strings.Builder
+	sinks := []converter.Sink{converter.JUnitSink{}, converter.GitHubSink{W: &annotations}, converter.AzureSink{W: &annotations}, converter.TeamCitySink{W: &annotations}, converter.AllureSink{}}
+	projects := map[string]converter.ProjectOptions{
+		"ext": {Sinks: []converter.Sink{converter.GitHubSink{W: &annotations}}},
+	}
+
+	parentSinks, parentProjects := baselineSinks(sinks, projects)
+
+	if len(parentSinks) != 1 {
+		t.Fatalf("expected only the junit sink, but got %v", parentSinks)
+	}
+	if parentProjects["ext"].Sinks == nil || len(parentProjects["ext"].Sinks) != 0 {
+		t.Errorf("expected no project sinks, but got %v", parentProjects["ext"].Sinks)
+	}
+
+	records := []converter.ErrorRecord{converter.NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "ОбщийМодуль.А.Модуль", "строка 3", "Известная"})}
+	err := converter.Process(context.Background(), records, converter.WriterOutput{W: &xmlData}, parentSinks, converter.Options{Name: "parent"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(xmlData.String(), "Известная") {
+		t.Errorf("expected the known error in the junit report, but got %q", xmlData.String())
+	}
+	if annotations.Len() != 0 {
+		t.Errorf("expected no annotations, but got %q", annotations.String())
+	}
+}
@@ -1,13 +1,17 @@
 package config
 
 func NewAppConfig() *AppConfig {
-	return &AppConfig{}
+	return &AppConfig{
+		Include: []string{"*.tsv"},
+		Source:  PluginConfig{Type: "edt_tsv"},
+		Sinks:   []PluginConfig{{Type: "junit"}},
+	}
 }
 
 type configLoader interface {
-	Load(filePath string)
+	Load(filePath string) error
 }
 
-func LoadConfig(config configLoader, filePath string) {
-	config.Load(filePath)
+func LoadConfig(config configLoader, filePath string) error {
+	return config.Load(filePath)
 }
@@ -1,30 +1,265 @@
 package config
 
 import (
-	"encoding/json"
-	"os"
+	"fmt"
 	"path/filepath"
+	"strings"
 )
 
 type AppConfig struct {
-	InputFileFolder  string   `json:"input_file_folder"`
-	OutputFileFolder string   `json:"output_file_folder"`
-	SkipCategories   []string `json:"skip_categories"`
-	SkipObjects      []string `json:"skip_objects"`
-	SkipSignificanceCcategories     []string `json:"skip_significance_categories"`
-	SkipErrorText     []string `json:"skip_error_text"`
-	SkipErrorsFile   string   `json:"skip_errors_file"`
-}
-
-func (c *AppConfig) Load(filePath string) {
-	workspace, _ := os.Getwd()
-	configData, err := os.ReadFile(filepath.Join(workspace, filePath))
+	Schema                     string                   `json:"$schema"`
+	Extends                    []string                 `json:"extends"`
+	Profiles                   map[string]Profile       `json:"profiles"`
+	InputFileFolder            string                   `json:"input_file_folder"`
+	OutputFileFolder           string                   `json:"output_file_folder"`
+	SourceDir                  string                   `json:"source_dir"`
+	Blame                      bool                     `json:"blame"`
+	OwnersFile                 string                   `json:"owners_file"`
+	GroupBy                    string                   `json:"group_by"`
+	Since                      string                   `json:"since"`
+	DiffFile                   string                   `json:"diff_file"`
+	Include                    []string                 `json:"include"`
+	Exclude                    []string                 `json:"exclude"`
+	MergedReport               string                   `json:"merged_report"`
+	SkipCategories             []string                 `json:"skip_categories"`
+	SkipObjects                []string                 `json:"skip_objects"`
+	SkipSignificanceCategories []string                 `json:"skip_significance_categories"`
+	SkipErrorText              []string                 `json:"skip_error_text"`
+	Severities                 map[string]string        `json:"severities"`
+	SkipErrorsFile             string                   `json:"skip_errors_file"`
+	SkipErrorsLineTolerance    int                      `json:"skip_errors_line_tolerance"`
+	Source                     PluginConfig             `json:"source"`
+	Filters                    []PluginConfig           `json:"filters"`
+	Sinks                      []PluginConfig           `json:"sinks"`
+	Projects                   map[string]ProjectConfig `json:"projects"`
+
+	// Profile selects one of the Profiles when the file is loaded.
+	Profile string `json:"-"`
+}
+
+// PluginConfig selects a registered source, filter or sink by type.
+type PluginConfig struct {
+	Type    string         `json:"type"`
+	Options map[string]any `json:"options"`
+}
+
+// ProjectConfig holds the rules for the records of one project,
+// the configuration or an extension, by the Project column.
+// The skip lists and the filters are applied in addition to the common ones,
+// the sinks, the output folder and the grouping replace the common ones when set.
+type ProjectConfig struct {
+	SkipCategories             []string       `json:"skip_categories"`
+	SkipObjects                []string       `json:"skip_objects"`
+	SkipSignificanceCategories []string       `json:"skip_significance_categories"`
+	SkipErrorText              []string       `json:"skip_error_text"`
+	Filters                    []PluginConfig `json:"filters"`
+	Sinks                      []PluginConfig `json:"sinks"`
+	OutputFileFolder           string         `json:"output_file_folder"`
+	GroupBy                    string         `json:"group_by"`
+}
+
+// Profile changes the settings when it is selected. Set replaces
+// the settings, Add appends to the lists.
+type Profile struct {
+	Set *AppConfig `json:"set"`
+	Add *AppConfig `json:"add"`
+}
+
+// Load reads the config file in JSON, YAML or TOML format together with
+// the files it extends and applies the selected profile. Relative paths
+// in every file are resolved against the directory of that file.
+func (c *AppConfig) Load(filePath string) error {
+	filePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return err
+	}
+
+	data, err := readTree(filePath, nil)
+	if err != nil {
+		return err
+	}
+
+	if c.Profile != "" {
+		if err := applyProfile(data, c.Profile); err != nil {
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+	}
+	delete(data, "profiles")
+
+	if err := decode(data, c); err != nil {
+		return fmt.Errorf("%s: %w", filePath, err)
+	}
+	return nil
+}
+
+// pathSettings are resolved against the directory of the config file.
+// The skip errors file stays relative to the input file folder.
+var pathSettings = []string{"input_file_folder", "output_file_folder"}
+
+// optionalPathSettings are resolved the same way unless they are empty.
+var optionalPathSettings = []string{"source_dir", "owners_file", "diff_file"}
+
+// resolvePaths makes the folders and the path options of the plugins
+// absolute, including the ones in the projects and the profiles.
+func resolvePaths(data map[string]any, baseDir string) {
+	for _, key := range pathSettings {
+		if path, ok := data[key].(string); ok {
+			data[key] = ResolvePath(baseDir, path)
+		}
+	}
+	for _, key := range optionalPathSettings {
+		if path, ok := data[key].(string); ok && path != "" {
+			data[key] = ResolvePath(baseDir, path)
+		}
+	}
+
+	if plugin, ok := data["source"].(map[string]any); ok {
+		resolvePluginPaths(plugin, baseDir)
+	}
+	for _, key := range []string{"filters", "sinks"} {
+		plugins, _ := data[key].([]any)
+		for _, item := range plugins {
+			if plugin, ok := item.(map[string]any); ok {
+				resolvePluginPaths(plugin, baseDir)
+			}
+		}
+	}
+
+	projects, _ := data["projects"].(map[string]any)
+	for _, item := range projects {
+		if project, ok := item.(map[string]any); ok {
+			resolvePaths(project, baseDir)
+		}
+	}
+
+	profiles, _ := data["profiles"].(map[string]any)
+	for _, item := range profiles {
+		profile, _ := item.(map[string]any)
+		for _, key := range []string{"set", "add"} {
+			if settings, ok := profile[key].(map[string]any); ok {
+				resolvePaths(settings, baseDir)
+			}
+		}
+	}
+}
+
+// resolvePluginPaths resolves the options named "file", "dir" or ending
+// with "_file" or "_dir".
+func resolvePluginPaths(plugin map[string]any, baseDir string) {
+	options, _ := plugin["options"].(map[string]any)
+	for name, value := range options {
+		path, ok := value.(string)
+		if !ok || !isPathOption(name) {
+			continue
+		}
+		options[name] = ResolvePath(baseDir, path)
+	}
+}
+
+func isPathOption(name string) bool {
+	return name == "file" || name == "dir" || strings.HasSuffix(name, "_file") || strings.HasSuffix(name, "_dir")
+}
+
+// ResolvePath joins a relative path to baseDir. Absolute paths and "-",
+// which stands for stdin or stdout, are returned unchanged.
+func ResolvePath(baseDir string, path string) string {
+	if path == "-" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
+
+// readTree reads the config file over the files it extends, in order.
+// Settings of a later file replace the earlier ones, profiles are
+// replaced by name.
+func readTree(filePath string, visiting []string) (map[string]any, error) {
+	for _, visited := range visiting {
+		if visited == filePath {
+			return nil, fmt.Errorf("config %s extends itself", filePath)
+		}
+	}
+	visiting = append(visiting, filePath)
+
+	data, err := readFile(filePath)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("%s: %w", filePath, err)
 	}
+	baseDir := filepath.Dir(filePath)
+	resolvePaths(data, baseDir)
 
-	err = json.Unmarshal(configData, c)
+	extends, err := extendsOf(data)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	delete(data, "extends")
+
+	merged := make(map[string]any)
+	for _, base := range extends {
+		baseData, err := readTree(ResolvePath(baseDir, base), visiting)
+		if err != nil {
+			return nil, err
+		}
+		merge(merged, baseData)
+	}
+	merge(merged, data)
+	return merged, nil
+}
+
+func extendsOf(data map[string]any) ([]string, error) {
+	items, ok := data["extends"].([]any)
+	if !ok && data["extends"] != nil {
+		return nil, fmt.Errorf("extends must be a list of files")
+	}
+
+	var extends []string
+	for _, item := range items {
+		base, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("extends must be a list of files")
+		}
+		extends = append(extends, base)
+	}
+	return extends, nil
+}
+
+func merge(dst map[string]any, src map[string]any) {
+	for key, value := range src {
+		if key == "profiles" {
+			profiles, _ := dst[key].(map[string]any)
+			if profiles == nil {
+				profiles = make(map[string]any)
+			}
+			srcProfiles, _ := value.(map[string]any)
+			for name, profile := range srcProfiles {
+				profiles[name] = profile
+			}
+			dst[key] = profiles
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+func applyProfile(data map[string]any, name string) error {
+	profiles, _ := data["profiles"].(map[string]any)
+	profile, found := profiles[name].(map[string]any)
+	if !found {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+
+	set, _ := profile["set"].(map[string]any)
+	for key, value := range set {
+		data[key] = value
+	}
+
+	add, _ := profile["add"].(map[string]any)
+	for key, value := range add {
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("profile %s: %s is not a list and cannot be added to", name, key)
+		}
+		existing, _ := data[key].([]any)
+		data[key] = append(append([]any{}, existing...), items...)
 	}
+	return nil
 }
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_ResolvesPathsAgainstConfigFolder(t *testing.T) {
+	dir := t.TempDir()
+	abs := filepath.Join(t.TempDir(), "out")
+	data := `{"input_file_folder": "in", "output_file_folder": "` + filepath.ToSlash(abs) + `", "skip_errors_file": "vendor.vd", "source_dir": "src",
+		"filters": [{"type": "baseline", "options": {"file": "base/vendor.vd", "values": "x"}}]}`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(data), 0666); err != nil {
+		t.Fatalf("failed writing config: %v", err)
+	}
+
+	c := NewAppConfig()
+	c.Load(filepath.Join(dir, "config.json"))
+
+	assert.Equal(t, filepath.Join(dir, "in"), c.InputFileFolder)
+	assert.Equal(t, abs, c.OutputFileFolder)
+	assert.Equal(t, filepath.Join(dir, "src"), c.SourceDir)
+	assert.Equal(t, "vendor.vd", c.SkipErrorsFile, "skip errors file stays relative to the input folder")
+	assert.Equal(t, filepath.Join(dir, "base", "vendor.vd"), c.Filters[0].Options["file"])
+	assert.Equal(t, "x", c.Filters[0].Options["values"])
+}
+
+func TestLoad_ExtendsAndProfiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"base/base.yaml": `
+input_file_folder: in
+skip_categories: [Предупреждение]
+skip_objects: [.Удалить]
+profiles:
+  nightly:
+    set:
+      skip_objects: []
+  release:
+    add:
+      skip_categories: [Производительность]
+`,
+		"repo/config.json": `{
+			"extends": ["../base/base.yaml"],
+			"output_file_folder": "out",
+			"skip_objects": [".Удалить", ".Старый"],
+			"profiles": {"nightly": {"set": {"output_file_folder": "nightly"}}}
+		}`,
+	}
+	for name, data := range files {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0777)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0666); err != nil {
+			t.Fatalf("failed writing config: %v", err)
+		}
+	}
+	configPath := filepath.Join(dir, "repo", "config.json")
+
+	c := NewAppConfig()
+	assert.NoError(t, c.Load(configPath))
+	assert.Equal(t, filepath.Join(dir, "base", "in"), c.InputFileFolder)
+	assert.Equal(t, filepath.Join(dir, "repo", "out"), c.OutputFileFolder)
+	assert.Equal(t, []string{"Предупреждение"}, c.SkipCategories)
+	assert.Equal(t, []string{".Удалить", ".Старый"}, c.SkipObjects)
+
+	c = NewAppConfig()
+	c.Profile = "nightly"
+	assert.NoError(t, c.Load(configPath))
+	assert.Equal(t, filepath.Join(dir, "repo", "nightly"), c.OutputFileFolder, "profile is replaced by name")
+	assert.Equal(t, []string{".Удалить", ".Старый"}, c.SkipObjects)
+
+	c = NewAppConfig()
+	c.Profile = "release"
+	assert.NoError(t, c.Load(configPath))
+	assert.Equal(t, []string{"Предупреждение", "Производительность"}, c.SkipCategories)
+
+	assert.Empty(t, CheckFile(configPath, "release"))
+	assert.Equal(t, "$.profiles: unknown profile \"missing\"", CheckFile(configPath, "missing")[0].String())
+
+	invalidPath := filepath.Join(dir, "repo", "invalid.json")
+	if err := os.WriteFile(invalidPath, []byte(`{"profiles": {"release": {"add": {"output_file_folder": "other"}}}}`), 0666); err != nil {
+		t.Fatalf("failed writing config: %v", err)
+	}
+	assert.Equal(t, []string{"$.profiles.release.add.output_file_folder: not a list and cannot be added to"}, problemStrings(CheckFile(invalidPath, "release")))
+	c = NewAppConfig()
+	c.Profile = "release"
+	assert.ErrorContains(t, c.Load(invalidPath), "output_file_folder is not a list")
+}
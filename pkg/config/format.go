@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// readFile decodes the config file into generic values, the format is
+// chosen by the extension: .yaml, .yml, .toml and JSON otherwise.
+// Values of every format are normalized to the types of encoding/json.
+func readFile(filePath string) (map[string]any, error) {
+	configData, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var data any
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(configData, &data)
+	case ".toml":
+		var table map[string]any
+		_, err = toml.Decode(string(configData), &table)
+		data = table
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(configData))
+		decoder.UseNumber()
+		err = decoder.Decode(&data)
+		var syntaxError *json.SyntaxError
+		if errors.As(err, &syntaxError) {
+			line, column := position(configData, syntaxError.Offset)
+			err = fmt.Errorf("line %d, column %d: %w", line, column, err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return map[string]any{}, nil
+	}
+
+	normalized, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]any
+	if err := json.Unmarshal(normalized, &object); err != nil {
+		return nil, fmt.Errorf("config must be an object")
+	}
+	return object, nil
+}
+
+// decode fills the config from generic values using the json field names.
+func decode(data any, c *AppConfig) error {
+	configData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(configData, c)
+}
+
+func position(data []byte, offset int64) (int, int) {
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len([]rune(string(before[bytes.LastIndexByte(before, '\n')+1:])))
+	return line, column
+}
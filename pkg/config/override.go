@@ -0,0 +1,232 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EnvPrefix starts the names of the environment variables overriding
+// the settings, e.g. CONV_EDT_INPUT_FILE_FOLDER for input_file_folder.
+const EnvPrefix = "CONV_EDT_"
+
+// setting describes how an AppConfig field is named on the command line.
+type setting struct {
+	key   string
+	flags []string
+	usage string
+}
+
+var settings = []setting{
+	{"input_file_folder", []string{"input", "i"}, "input file folder, '-' reads results from stdin"},
+	{"output_file_folder", []string{"output", "o"}, "output file folder, '-' writes reports to stdout"},
+	{"source_dir", []string{"source-dir"}, "folder with the sources of the checked projects"},
+	{"blame", []string{"blame"}, "annotate the errors with git blame of the source folder"},
+	{"owners_file", []string{"owners-file"}, "CODEOWNERS style file assigning the errors to the teams"},
+	{"group_by", []string{"group-by"}, "record field, e.g. author, to write a report per value"},
+	{"since", []string{"since"}, "git ref, only errors on the lines changed since it are reported"},
+	{"diff_file", []string{"diff-file"}, "unified diff, only errors on the changed lines are reported"},
+	{"include", []string{"include"}, "glob of the input files relative to the input folder, repeatable"},
+	{"exclude", []string{"exclude"}, "glob of the input files or folders to skip, repeatable"},
+	{"merged_report", []string{"merged-report"}, "name of the single report over all input files"},
+	{"skip_errors_file", []string{"skip-errors-file"}, "file with the errors to skip"},
+	{"skip_errors_line_tolerance", []string{"skip-errors-line-tolerance"}, "lines an error of the skip errors file may move by"},
+	{"skip_categories", []string{"skip-category"}, "category to skip, repeatable"},
+	{"skip_objects", []string{"skip-object"}, "object to skip, repeatable"},
+	{"skip_significance_categories", []string{"skip-significance-category"}, "Significance_Category to skip, repeatable"},
+	{"skip_error_text", []string{"skip-error-text"}, "error text to skip, repeatable"},
+	{"severities", []string{"severities"}, "json object mapping significances to error, warning or info"},
+	{"source", []string{"source"}, "source as type or type={json options}"},
+	{"filters", []string{"filter"}, "filter as type or type={json options}, repeatable"},
+	{"sinks", []string{"sink"}, "sink as type or type={json options}, repeatable"},
+	{"projects", []string{"projects"}, "per-project settings as json object"},
+}
+
+// field returns the AppConfig field with the json key.
+func (c *AppConfig) field(key string) reflect.Value {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if strings.Split(t.Field(i).Tag.Get("json"), ",")[0] == key {
+			return v.Field(i)
+		}
+	}
+	panic("config: unknown setting " + key)
+}
+
+// setValue parses a flag or environment value into the field.
+// Lists take a json array or comma separated values, plugins take
+// a type name optionally followed by "=" and json options, other
+// settings take json.
+func setValue(field reflect.Value, value string) error {
+	switch field.Interface().(type) {
+	case string:
+		field.SetString(value)
+	case []string:
+		var values []string
+		if strings.HasPrefix(strings.TrimSpace(value), "[") {
+			if err := json.Unmarshal([]byte(value), &values); err != nil {
+				return err
+			}
+		} else {
+			for _, item := range strings.Split(value, ",") {
+				values = append(values, strings.TrimSpace(item))
+			}
+		}
+		field.Set(reflect.ValueOf(nonBlank(values)))
+	case PluginConfig:
+		plugin, err := parsePlugin(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(plugin))
+	case []PluginConfig:
+		var plugins []PluginConfig
+		if strings.HasPrefix(strings.TrimSpace(value), "[") {
+			if err := json.Unmarshal([]byte(value), &plugins); err != nil {
+				return err
+			}
+		} else {
+			plugin, err := parsePlugin(value)
+			if err != nil {
+				return err
+			}
+			plugins = append(plugins, plugin)
+		}
+		field.Set(reflect.ValueOf(plugins))
+	default:
+		decoded := reflect.New(field.Type())
+		if err := json.Unmarshal([]byte(value), decoded.Interface()); err != nil {
+			return err
+		}
+		field.Set(decoded.Elem())
+	}
+	return nil
+}
+
+// nonBlank drops the blank items of a list, so an empty value, e.g. of
+// an unset CI variable, is an empty list rather than a list matching everything.
+func nonBlank(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func parsePlugin(value string) (PluginConfig, error) {
+	pluginType, options, found := strings.Cut(value, "=")
+	plugin := PluginConfig{Type: strings.TrimSpace(pluginType)}
+	if found {
+		if err := json.Unmarshal([]byte(options), &plugin.Options); err != nil {
+			return PluginConfig{}, fmt.Errorf("options of %s: %w", plugin.Type, err)
+		}
+	}
+	return plugin, nil
+}
+
+// LoadEnv overrides the settings from CONV_EDT_* variables in the
+// "NAME=value" form of os.Environ.
+func (c *AppConfig) LoadEnv(environ []string) error {
+	values := make(map[string]string)
+	for _, item := range environ {
+		name, value, _ := strings.Cut(item, "=")
+		values[name] = value
+	}
+
+	for _, s := range settings {
+		name := EnvPrefix + strings.ToUpper(s.key)
+		value, found := values[name]
+		if !found {
+			continue
+		}
+		if err := setValue(c.field(s.key), value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Flags holds the settings given on the command line.
+type Flags struct {
+	values map[string]*[]string
+}
+
+// flagValue collects every occurrence of a flag.
+type flagValue struct {
+	values *[]string
+}
+
+func (f flagValue) String() string {
+	if f.values == nil {
+		return ""
+	}
+	return strings.Join(*f.values, ",")
+}
+
+func (f flagValue) Set(value string) error {
+	*f.values = append(*f.values, value)
+	return nil
+}
+
+// boolFlagValue is a flag of a boolean setting, it may be given without a value.
+type boolFlagValue struct {
+	flagValue
+}
+
+func (boolFlagValue) IsBoolFlag() bool {
+	return true
+}
+
+// RegisterFlags defines a flag for every setting, see Flags.Apply.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{values: make(map[string]*[]string)}
+	for _, s := range settings {
+		values := new([]string)
+		var value flag.Value = flagValue{values}
+		if new(AppConfig).field(s.key).Kind() == reflect.Bool {
+			value = boolFlagValue{flagValue{values}}
+		}
+		for _, name := range s.flags {
+			fs.Var(value, name, s.usage)
+		}
+		f.values[s.key] = values
+	}
+	return f
+}
+
+// Apply overrides the settings given on the command line. Every occurrence
+// of a list flag adds one item, the list from the config is replaced.
+func (f *Flags) Apply(c *AppConfig) error {
+	for _, s := range settings {
+		values := *f.values[s.key]
+		if len(values) == 0 {
+			continue
+		}
+
+		field := c.field(s.key)
+		switch field.Interface().(type) {
+		case []string:
+			field.Set(reflect.ValueOf(nonBlank(values)))
+		case []PluginConfig:
+			var plugins []PluginConfig
+			for _, value := range values {
+				plugin, err := parsePlugin(value)
+				if err != nil {
+					return fmt.Errorf("--%s: %w", s.flags[0], err)
+				}
+				plugins = append(plugins, plugin)
+			}
+			field.Set(reflect.ValueOf(plugins))
+		default:
+			if err := setValue(field, values[len(values)-1]); err != nil {
+				return fmt.Errorf("--%s: %w", s.flags[0], err)
+			}
+		}
+	}
+	return nil
+}
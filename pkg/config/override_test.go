@@ -0,0 +1,70 @@
+package config
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadEnv(t *testing.T) {
+	c := NewAppConfig()
+	c.InputFileFolder = "from_config"
+
+	err := c.LoadEnv([]string{
+		"CONV_EDT_INPUT_FILE_FOLDER=in",
+		"CONV_EDT_SKIP_CATEGORIES=Предупреждение, Производительность",
+		`CONV_EDT_SKIP_ERROR_TEXT=["a, b"]`,
+		`CONV_EDT_SINKS=junit={"x":1}`,
+		"OTHER=value",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "in", c.InputFileFolder)
+	assert.Equal(t, []string{"Предупреждение", "Производительность"}, c.SkipCategories)
+	assert.Equal(t, []string{"a, b"}, c.SkipErrorText)
+	assert.Equal(t, []PluginConfig{{Type: "junit", Options: map[string]any{"x": float64(1)}}}, c.Sinks)
+}
+
+func TestLoadEnv_EmptyLists(t *testing.T) {
+	c := NewAppConfig()
+	c.SkipObjects = []string{"from_config"}
+
+	err := c.LoadEnv([]string{
+		"CONV_EDT_SKIP_OBJECTS=",
+		"CONV_EDT_SKIP_CATEGORIES=a, ,b,",
+		`CONV_EDT_SKIP_ERROR_TEXT=["", " "]`,
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, c.SkipObjects)
+	assert.Equal(t, []string{"a", "b"}, c.SkipCategories)
+	assert.Empty(t, c.SkipErrorText)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	c.SkipCategories = []string{"from_config"}
+	assert.NoError(t, fs.Parse([]string{"--skip-category", "", "--skip-object", " "}))
+	assert.NoError(t, flags.Apply(c))
+	assert.Empty(t, c.SkipCategories)
+	assert.Empty(t, c.SkipObjects)
+}
+
+func TestFlagsApply(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	c := NewAppConfig()
+	c.OutputFileFolder = "from_config"
+	c.SkipCategories = []string{"from_config"}
+
+	err := fs.Parse([]string{"-i", "-", "--skip-category", "a, b", "--skip-category", "c", "--filter", `baseline={"file":"vendor.vd"}`, "--blame"})
+	assert.NoError(t, err)
+	err = flags.Apply(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "-", c.InputFileFolder)
+	assert.Equal(t, "from_config", c.OutputFileFolder)
+	assert.Equal(t, []string{"a, b", "c"}, c.SkipCategories)
+	assert.Equal(t, []PluginConfig{{Type: "baseline", Options: map[string]any{"file": "vendor.vd"}}}, c.Filters)
+	assert.True(t, c.Blame)
+}
@@ -0,0 +1,8 @@
+package config
+
+import _ "embed"
+
+// Schema is the JSON Schema of the config file for editor autocompletion.
+//
+//go:embed config.schema.json
+var Schema []byte
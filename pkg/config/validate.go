@@ -0,0 +1,331 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Problem is a config error found by the checks, Path is the JSON path
+// of the value, e.g. $.skip_significance_categories[1].
+type Problem struct {
+	Path    string
+	Message string
+
+	// fatal problems prevent loading the config file.
+	fatal bool
+}
+
+// Fatal reports whether any of the problems prevents loading the config file.
+func Fatal(problems []Problem) bool {
+	for _, problem := range problems {
+		if problem.fatal {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Problem) String() string {
+	return p.Path + ": " + p.Message
+}
+
+// CheckFile reports syntax errors, unknown keys and values of a wrong type
+// in the config file and the files it extends, which Load silently ignores,
+// and checks that the profile, if any, is defined.
+func CheckFile(filePath string, profile string) []Problem {
+	filePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return []Problem{{Path: "$", Message: err.Error(), fatal: true}}
+	}
+
+	profiles := make(map[string]struct{})
+	problems := checkFile(filePath, "", nil, profiles)
+	if _, found := profiles[profile]; profile != "" && !found && !Fatal(problems) {
+		problems = append(problems, Problem{Path: "$.profiles", Message: fmt.Sprintf("unknown profile %q", profile), fatal: true})
+	}
+	return problems
+}
+
+// checkFile checks one file of the tree, the paths of the problems
+// in the extended files are prefixed with the file name.
+func checkFile(filePath string, prefix string, visiting []string, profiles map[string]struct{}) []Problem {
+	for _, visited := range visiting {
+		if visited == filePath {
+			return []Problem{{Path: prefix + "$.extends", Message: "config extends itself", fatal: true}}
+		}
+	}
+	visiting = append(visiting, filePath)
+
+	data, err := readFile(filePath)
+	if err != nil {
+		return []Problem{{Path: prefix + "$", Message: err.Error(), fatal: true}}
+	}
+
+	var problems []Problem
+	checkValue(prefix+"$", reflect.TypeOf(AppConfig{}), data, &problems)
+	if Fatal(problems) {
+		return problems
+	}
+
+	definedProfiles, _ := data["profiles"].(map[string]any)
+	for name := range definedProfiles {
+		profiles[name] = struct{}{}
+	}
+	problems = append(problems, checkProfileLists(prefix+"$.profiles", definedProfiles)...)
+	if Fatal(problems) {
+		return problems
+	}
+
+	extends, _ := extendsOf(data)
+	for _, base := range extends {
+		problems = append(problems, checkFile(ResolvePath(filepath.Dir(filePath), base), base+":", visiting, profiles)...)
+	}
+	return problems
+}
+
+// checkValue compares the decoded value with the type of the config field.
+// Plugin options are checked by the plugins themselves.
+func checkValue(path string, t reflect.Type, value any, problems *[]Problem) {
+	if value == nil {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			*problems = append(*problems, Problem{Path: path, Message: "expected object", fatal: true})
+			return
+		}
+		fields := jsonFields(t)
+		for _, key := range sortedKeys(object) {
+			field, found := fields[key]
+			if !found {
+				*problems = append(*problems, Problem{Path: path + "." + key, Message: "unknown key"})
+				continue
+			}
+			checkValue(path+"."+key, field.Type, object[key], problems)
+		}
+	case reflect.Slice:
+		items, ok := value.([]any)
+		if !ok {
+			*problems = append(*problems, Problem{Path: path, Message: "expected array", fatal: true})
+			return
+		}
+		for i, item := range items {
+			checkValue(fmt.Sprintf("%s[%d]", path, i), t.Elem(), item, problems)
+		}
+	case reflect.Pointer:
+		checkValue(path, t.Elem(), value, problems)
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok {
+			*problems = append(*problems, Problem{Path: path, Message: "expected object", fatal: true})
+			return
+		}
+		for _, key := range sortedKeys(object) {
+			checkValue(path+"."+key, t.Elem(), object[key], problems)
+		}
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			*problems = append(*problems, Problem{Path: path, Message: "expected string", fatal: true})
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			*problems = append(*problems, Problem{Path: path, Message: "expected boolean", fatal: true})
+		}
+	case reflect.Int:
+		if number, ok := value.(float64); !ok || number != math.Trunc(number) {
+			*problems = append(*problems, Problem{Path: path, Message: "expected integer", fatal: true})
+		}
+	}
+}
+
+// checkProfileLists reports the settings added by the profiles which
+// are not lists, Load cannot apply such profiles.
+func checkProfileLists(path string, profiles map[string]any) []Problem {
+	var problems []Problem
+	fields := jsonFields(reflect.TypeOf(AppConfig{}))
+	for _, name := range sortedKeys(profiles) {
+		profile, _ := profiles[name].(map[string]any)
+		add, _ := profile["add"].(map[string]any)
+		for _, key := range sortedKeys(add) {
+			if field, found := fields[key]; found && field.Type.Kind() != reflect.Slice {
+				problems = append(problems, Problem{Path: path + "." + name + ".add." + key, Message: "not a list and cannot be added to", fatal: true})
+			}
+		}
+	}
+	return problems
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = t.Field(i)
+		}
+	}
+	return fields
+}
+
+// Check reports settings which cannot work: missing folders and files
+// and malformed values.
+func (c *AppConfig) Check() []Problem {
+	var problems []Problem
+
+	if c.InputFileFolder != "-" {
+		if err := checkDir(c.InputFileFolder); err != nil {
+			problems = append(problems, Problem{Path: "$.input_file_folder", Message: err.Error()})
+		}
+	}
+	if c.OutputFileFolder != "-" {
+		if err := checkDir(c.OutputFileFolder); err != nil {
+			problems = append(problems, Problem{Path: "$.output_file_folder", Message: err.Error()})
+		}
+	}
+
+	problems = append(problems, c.CheckStdout()...)
+
+	if c.SourceDir != "" {
+		if err := checkDir(c.SourceDir); err != nil {
+			problems = append(problems, Problem{Path: "$.source_dir", Message: err.Error()})
+		}
+	}
+
+	if c.Blame && c.SourceDir == "" {
+		problems = append(problems, Problem{Path: "$.blame", Message: "source_dir is required"})
+	}
+
+	if c.DiffFile != "" {
+		if _, err := os.Stat(c.DiffFile); err != nil {
+			problems = append(problems, Problem{Path: "$.diff_file", Message: err.Error()})
+		}
+	}
+
+	if c.SkipErrorsFile != "" {
+		base := c.InputFileFolder
+		if base == "-" {
+			base = ""
+		}
+		if _, err := os.Stat(ResolvePath(base, c.SkipErrorsFile)); err != nil {
+			problems = append(problems, Problem{Path: "$.skip_errors_file", Message: err.Error()})
+		}
+	}
+
+	if c.SkipErrorsLineTolerance < 0 {
+		problems = append(problems, Problem{Path: "$.skip_errors_line_tolerance", Message: "must not be negative"})
+	}
+
+	problems = append(problems, checkPatterns("$.include", c.Include)...)
+	problems = append(problems, checkPatterns("$.exclude", c.Exclude)...)
+	problems = append(problems, checkSignificanceCategories("$", c.SkipSignificanceCategories)...)
+
+	if c.Source.Type == "" {
+		problems = append(problems, Problem{Path: "$.source.type", Message: "type is required"})
+	}
+	for i, filter := range c.Filters {
+		if filter.Type == "" {
+			problems = append(problems, Problem{Path: fmt.Sprintf("$.filters[%d].type", i), Message: "type is required"})
+		}
+	}
+	for i, sink := range c.Sinks {
+		if sink.Type == "" {
+			problems = append(problems, Problem{Path: fmt.Sprintf("$.sinks[%d].type", i), Message: "type is required"})
+		}
+	}
+
+	for name, project := range c.Projects {
+		path := "$.projects." + name
+		if project.OutputFileFolder != "" {
+			if err := checkDir(project.OutputFileFolder); err != nil {
+				problems = append(problems, Problem{Path: path + ".output_file_folder", Message: err.Error()})
+			}
+		}
+		problems = append(problems, checkSignificanceCategories(path, project.SkipSignificanceCategories)...)
+		for i, filter := range project.Filters {
+			if filter.Type == "" {
+				problems = append(problems, Problem{Path: fmt.Sprintf("%s.filters[%d].type", path, i), Message: "type is required"})
+			}
+		}
+		for i, sink := range project.Sinks {
+			if sink.Type == "" {
+				problems = append(problems, Problem{Path: fmt.Sprintf("%s.sinks[%d].type", path, i), Message: "type is required"})
+			}
+		}
+	}
+	return problems
+}
+
+// CheckStdout reports the settings writing several documents to stdout:
+// the reports of several input files, projects or groups and the output
+// of several sinks, including the sinks which print to stdout themselves.
+func (c *AppConfig) CheckStdout() []Problem {
+	if c.OutputFileFolder != "-" {
+		return nil
+	}
+
+	var problems []Problem
+	if c.InputFileFolder != "-" && c.MergedReport == "" {
+		problems = append(problems, Problem{Path: "$.output_file_folder", Message: "stdout requires input from stdin or merged_report"})
+	}
+	if len(c.Sinks) > 1 {
+		problems = append(problems, Problem{Path: "$.sinks", Message: "stdout takes a single sink"})
+	}
+	if c.GroupBy != "" {
+		problems = append(problems, Problem{Path: "$.group_by", Message: "stdout takes a single report"})
+	}
+	if len(c.Projects) > 0 {
+		problems = append(problems, Problem{Path: "$.projects", Message: "stdout takes a single report"})
+	}
+	return problems
+}
+
+func checkPatterns(path string, patterns []string) []Problem {
+	var problems []Problem
+	for i, pattern := range patterns {
+		for _, element := range strings.Split(pattern, "/") {
+			if _, err := filepath.Match(element, ""); err != nil {
+				problems = append(problems, Problem{Path: fmt.Sprintf("%s[%d]", path, i), Message: fmt.Sprintf("%q: %s", pattern, err.Error())})
+				break
+			}
+		}
+	}
+	return problems
+}
+
+func checkSignificanceCategories(path string, values []string) []Problem {
+	var problems []Problem
+	for i, value := range values {
+		significance, category, found := strings.Cut(value, "_")
+		if !found || significance == "" || category == "" {
+			problems = append(problems, Problem{Path: fmt.Sprintf("%s.skip_significance_categories[%d]", path, i), Message: fmt.Sprintf("%q does not have the Significance_Category shape", value)})
+		}
+	}
+	return problems
+}
+
+func checkDir(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.json")
+	data := `{"skip_categorys": [], "skip_objects": "x", "filters": [{"type": "baseline", "option": {}}], "severities": {"Ошибка": "error", "x": 1}}`
+	if err := os.WriteFile(filePath, []byte(data), 0666); err != nil {
+		t.Fatalf("failed writing config: %v", err)
+	}
+
+	problems := CheckFile(filePath, "")
+
+	assert.Equal(t, []string{
+		"$.filters[0].option: unknown key",
+		"$.severities.x: expected string",
+		"$.skip_categorys: unknown key",
+		"$.skip_objects: expected array",
+	}, problemStrings(problems))
+	assert.True(t, Fatal(problems))
+}
+
+func TestCheckFile_SyntaxError(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(filePath, []byte("{\n  \"input_file_folder\": ,\n}"), 0666); err != nil {
+		t.Fatalf("failed writing config: %v", err)
+	}
+
+	problems := CheckFile(filePath, "")
+
+	assert.Len(t, problems, 1)
+	assert.Contains(t, problems[0].String(), "$: line 2, column 24")
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	c := NewAppConfig()
+	c.InputFileFolder = dir
+	c.OutputFileFolder = filepath.Join(dir, "missing")
+	c.SkipSignificanceCategories = []string{"Значительная_Переносимость", "Переносимость", "_Переносимость"}
+
+	problems := c.Check()
+
+	assert.Len(t, problems, 3)
+	assert.Equal(t, "$.output_file_folder", problems[0].Path)
+	assert.Equal(t, "$.skip_significance_categories[1]", problems[1].Path)
+	assert.Equal(t, "$.skip_significance_categories[2]", problems[2].Path)
+	assert.False(t, Fatal(problems))
+}
+
+func problemStrings(problems []Problem) []string {
+	var result []string
+	for _, problem := range problems {
+		result = append(result, problem.String())
+	}
+	return result
+}
+
+func TestSchema_DescribesEverySetting(t *testing.T) {
+	var schema struct {
+		Properties map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal(Schema, &schema); err != nil {
+		t.Fatalf("failed parsing schema: %v", err)
+	}
+
+	var keys []string
+	for key := range jsonFields(reflect.TypeOf(AppConfig{})) {
+		keys = append(keys, key)
+		assert.Contains(t, schema.Properties, key)
+	}
+	assert.Len(t, schema.Properties, len(keys))
+}
+
+func TestCheckFile_YAMLAndTOML(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.yaml": "# comment\ninput_file_folder: in\nskip_categorys: []\nskip_objects:\n  - .Удалить # comment\n",
+		"config.toml": "# comment\ninput_file_folder = \"in\"\nskip_categorys = []\nskip_objects = [\".Удалить\"]\n",
+	}
+	for name, data := range files {
+		filePath := filepath.Join(dir, name)
+		if err := os.WriteFile(filePath, []byte(data), 0666); err != nil {
+			t.Fatalf("failed writing config: %v", err)
+		}
+
+		assert.Equal(t, []string{"$.skip_categorys: unknown key"}, problemStrings(CheckFile(filePath, "")), name)
+
+		c := NewAppConfig()
+		assert.NoError(t, c.Load(filePath), name)
+		assert.Equal(t, filepath.Join(dir, "in"), c.InputFileFolder, name)
+		assert.Equal(t, []string{".Удалить"}, c.SkipObjects, name)
+	}
+}
+
+func TestCheck_NegativeLineTolerance(t *testing.T) {
+	c := NewAppConfig()
+	c.InputFileFolder = t.TempDir()
+	c.OutputFileFolder = t.TempDir()
+	c.SkipErrorsLineTolerance = -1
+
+	assert.Equal(t, []Problem{{Path: "$.skip_errors_line_tolerance", Message: "must not be negative"}}, c.Check())
+}
+
+func TestCheck_StdoutRequiresStdinOrMergedReport(t *testing.T) {
+	c := NewAppConfig()
+	c.InputFileFolder = t.TempDir()
+	c.OutputFileFolder = "-"
+
+	assert.Equal(t, []Problem{{Path: "$.output_file_folder", Message: "stdout requires input from stdin or merged_report"}}, c.Check())
+
+	c.MergedReport = "validation"
+	assert.Empty(t, c.Check())
+
+	c.Sinks = []PluginConfig{{Type: "junit"}, {Type: "github"}}
+	c.GroupBy = "owner"
+	assert.Equal(t, []string{
+		"$.sinks: stdout takes a single sink",
+		"$.group_by: stdout takes a single report",
+	}, problemStrings(c.Check()))
+}
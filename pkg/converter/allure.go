@@ -0,0 +1,188 @@
+package converter
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"time"
+)
+
+// DefaultAllureFolder is the folder of the allure results in the output.
+const DefaultAllureFolder = "allure-results"
+
+// allureSeverities map the significances of EDT to the allure severities,
+// the others are mapped by the record severity.
+var allureSeverities = map[string]string{
+	"Блокирующая":    "blocker",
+	"Критическая":    "critical",
+	"Значительная":   "normal",
+	"Незначительная": "minor",
+	"Тривиальная":    "trivial",
+	"Blocker":        "blocker",
+	"Critical":       "critical",
+	"Major":          "normal",
+	"Minor":          "minor",
+	"Trivial":        "trivial",
+}
+
+// AllureSink writes a <uuid>-result.json file for every test case, see
+// NewTestSuites, into the allure results folder.
+type AllureSink struct {
+	// Folder in the output, empty means DefaultAllureFolder.
+	Folder string
+
+	// Logger receives debug messages, nil means slog.Default.
+	Logger *slog.Logger
+}
+
+type allureResult struct {
+	UUID          string              `json:"uuid"`
+	HistoryID     string              `json:"historyId"`
+	TestCaseID    string              `json:"testCaseId"`
+	FullName      string              `json:"fullName"`
+	Name          string              `json:"name"`
+	Status        string              `json:"status"`
+	StatusDetails allureStatusDetails `json:"statusDetails"`
+	Stage         string              `json:"stage"`
+	Start         int64               `json:"start"`
+	Stop          int64               `json:"stop"`
+	Labels        []allureLabel       `json:"labels"`
+}
+
+type allureStatusDetails struct {
+	Message string `json:"message"`
+	Trace   string `json:"trace"`
+}
+
+type allureLabel struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (s AllureSink) Write(ctx context.Context, out Output, report Report) error {
+	folder := s.Folder
+	if folder == "" {
+		folder = DefaultAllureFolder
+	}
+	logger := loggerOrDefault(s.Logger)
+
+	// occurrences tell apart the same errors of a module, so that allure
+	// does not take them for the retries of one test.
+	occurrences := make(map[string]int)
+	for _, ts := range NewTestSuites(report, logger).TestSuite {
+		for _, tc := range ts.TestCases {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			f := tc.Failures[0]
+			record := f.record
+			testCaseID := record.Fingerprint()
+			occurrences[testCaseID]++
+
+			uuid, err := newUUID()
+			if err != nil {
+				return err
+			}
+			start := recordTime(record).UnixMilli()
+			result := allureResult{
+				UUID:          uuid,
+				HistoryID:     fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%s:%d", testCaseID, occurrences[testCaseID])))),
+				TestCaseID:    testCaseID,
+				FullName:      ts.Name + "." + tc.Name,
+				Name:          tc.Name,
+				Status:        "failed",
+				StatusDetails: allureStatusDetails{Message: record.ErrorText, Trace: f.Text},
+				Stage:         "finished",
+				Start:         start,
+				Stop:          start,
+				Labels:        allureLabels(record),
+			}
+
+			err = writeFile(out, path.Join(folder, uuid+"-result.json"), func(w io.Writer) error {
+				encoder := json.NewEncoder(w)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(result)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func allureLabels(record ErrorRecord) []allureLabel {
+	suite := record.CheckType
+	if suite == "" {
+		suite = record.Priority
+	}
+	labels := []allureLabel{
+		{Name: "parentSuite", Value: record.Source},
+		{Name: "suite", Value: suite},
+		{Name: "feature", Value: topObject(record.ErrorModule)},
+		{Name: "story", Value: record.ErrorModule},
+		{Name: "severity", Value: allureSeverity(record)},
+	}
+	if record.Project != "" {
+		labels = append(labels, allureLabel{Name: "tag", Value: record.Project})
+	}
+	if record.Owner != "" {
+		labels = append(labels, allureLabel{Name: "owner", Value: record.Owner})
+	}
+	return labels
+}
+
+func allureSeverity(record ErrorRecord) string {
+	if severity, found := allureSeverities[record.Priority]; found {
+		return severity
+	}
+	switch record.Severity {
+	case SeverityError:
+		return "critical"
+	case SeverityInfo:
+		return "minor"
+	}
+	return "normal"
+}
+
+// recordTime is the time of the check, the current time when the date
+// cannot be parsed.
+func recordTime(record ErrorRecord) time.Time {
+	if t, err := time.Parse("2006-01-02T15:04:05-0700", record.Date); err == nil {
+		return t
+	}
+	return time.Now()
+}
+
+// newUUID returns a random UUID of version 4.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
+
+type allureOptions struct {
+	Folder string `json:"folder"`
+}
+
+func init() {
+	RegisterSink("allure", func(options map[string]any) (Sink, error) {
+		var opts allureOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return AllureSink{Folder: opts.Folder}, nil
+	})
+}
@@ -0,0 +1,142 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// GitHubSink prints a workflow command for every record, GitHub Actions
+// shows them as annotations of the source lines.
+type GitHubSink struct {
+	// PathPrefix is the path of Options.SourceDir in the repository, e.g. "src/".
+	PathPrefix string
+	// W receives the commands, nil means os.Stdout.
+	W io.Writer
+}
+
+var (
+	githubDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+func (s GitHubSink) Write(ctx context.Context, out Output, report Report) error {
+	w := writerOrStdout(s.W)
+	for _, record := range report.Records {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		level := record.Severity
+		if level == SeverityInfo {
+			level = "notice"
+		}
+		properties := []string{"title=" + githubPropertyEscaper.Replace(annotationTitle(record))}
+		if record.Path != "" {
+			properties = append(properties, "file="+githubPropertyEscaper.Replace(s.PathPrefix+record.Path))
+			if record.Line > 0 {
+				properties = append(properties, fmt.Sprintf("line=%d", record.Line))
+			}
+			if record.Column > 0 {
+				properties = append(properties, fmt.Sprintf("col=%d", record.Column))
+			}
+		}
+
+		_, err := fmt.Fprintf(w, "::%s %s::%s\n", level, strings.Join(properties, ","), githubDataEscaper.Replace(annotationMessage(record)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AzureSink prints a logging command for every record, Azure Pipelines
+// shows them as errors and warnings of the build.
+type AzureSink struct {
+	// PathPrefix is the path of Options.SourceDir in the repository, e.g. "src/".
+	PathPrefix string
+	// W receives the commands, nil means os.Stdout.
+	W io.Writer
+}
+
+var (
+	azureDataEscaper     = strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A")
+	azurePropertyEscaper = strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A", ";", "%3B", "]", "%5D")
+)
+
+func (s AzureSink) Write(ctx context.Context, out Output, report Report) error {
+	w := writerOrStdout(s.W)
+	for _, record := range report.Records {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Azure Pipelines has only errors and warnings.
+		level := SeverityWarning
+		if record.Severity == SeverityError {
+			level = SeverityError
+		}
+		properties := []string{"type=" + level}
+		if record.Path != "" {
+			properties = append(properties, "sourcepath="+azurePropertyEscaper.Replace(s.PathPrefix+record.Path))
+			if record.Line > 0 {
+				properties = append(properties, fmt.Sprintf("linenumber=%d", record.Line))
+			}
+			if record.Column > 0 {
+				properties = append(properties, fmt.Sprintf("columnnumber=%d", record.Column))
+			}
+		}
+		if record.Standard != "" {
+			properties = append(properties, "code="+azurePropertyEscaper.Replace(record.Standard))
+		}
+
+		_, err := fmt.Fprintf(w, "##vso[task.logissue %s;]%s\n", strings.Join(properties, ";"), azureDataEscaper.Replace(annotationTitle(record)+": "+annotationMessage(record)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func annotationTitle(record ErrorRecord) string {
+	if record.CheckType == "" {
+		return record.Priority
+	}
+	return record.Priority + ", " + record.CheckType
+}
+
+// annotationMessage keeps the module and the location column, which
+// are the only location of the records without a path.
+func annotationMessage(record ErrorRecord) string {
+	return record.ErrorModule + "; " + record.ErrorLine + "; " + record.ErrorText
+}
+
+func writerOrStdout(w io.Writer) io.Writer {
+	if w == nil {
+		return os.Stdout
+	}
+	return w
+}
+
+type annotationOptions struct {
+	PathPrefix string `json:"path_prefix"`
+}
+
+func init() {
+	RegisterSink("github", func(options map[string]any) (Sink, error) {
+		var opts annotationOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return GitHubSink{PathPrefix: opts.PathPrefix}, nil
+	})
+	RegisterSink("azure", func(options map[string]any) (Sink, error) {
+		var opts annotationOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return AzureSink{PathPrefix: opts.PathPrefix}, nil
+	})
+}
@@ -0,0 +1,51 @@
+package converter
+
+// Baseline holds the known errors which are skipped. A record matches the
+// baseline by Key, or, when LineTolerance is positive, by the same error on
+// a line moved by at most LineTolerance lines.
+type Baseline struct {
+	LineTolerance int
+
+	keys  map[string]struct{}
+	lines map[string][]int
+}
+
+// NewBaseline indexes the records of the skip errors file.
+func NewBaseline(records []ErrorRecord, lineTolerance int) *Baseline {
+	b := &Baseline{
+		LineTolerance: lineTolerance,
+		keys:          BaselineKeys(records),
+		lines:         make(map[string][]int),
+	}
+	for _, record := range records {
+		if record.Line > 0 {
+			key := record.locationFreeKey()
+			b.lines[key] = append(b.lines[key], record.Line)
+		}
+	}
+	return b
+}
+
+// Contains reports whether the record is a known error.
+func (b *Baseline) Contains(record ErrorRecord) bool {
+	if recordInSkipErrorsList(record, b.keys) {
+		return true
+	}
+	if b.LineTolerance <= 0 || record.Line <= 0 {
+		return false
+	}
+
+	for _, line := range b.lines[record.locationFreeKey()] {
+		if abs(line-record.Line) <= b.LineTolerance {
+			return true
+		}
+	}
+	return false
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
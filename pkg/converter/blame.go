@@ -0,0 +1,137 @@
+package converter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// uncommitted is the commit git blame reports for the changed lines
+// which are not committed yet.
+const uncommitted = "0000000000000000000000000000000000000000"
+
+// BlameLine is the last change of a source line.
+type BlameLine struct {
+	Commit     string
+	Author     string
+	AuthorMail string
+}
+
+// Blame returns the last change of every line of the file, the path is
+// relative to the git working copy dir.
+func Blame(dir string, path string) ([]BlameLine, error) {
+	output, err := gitOutput(dir, "blame", "--line-porcelain", "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []BlameLine
+	var line BlameLine
+	var number int
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "\t"):
+			for len(lines) < number {
+				lines = append(lines, BlameLine{})
+			}
+			if number > 0 {
+				lines[number-1] = line
+			}
+			line = BlameLine{}
+		case strings.HasPrefix(text, "author "):
+			line.Author = strings.TrimPrefix(text, "author ")
+		case strings.HasPrefix(text, "author-mail "):
+			line.AuthorMail = strings.Trim(strings.TrimPrefix(text, "author-mail "), "<>")
+		default:
+			// The header of the line: <commit> <original line> <final line> [<lines>].
+			fields := strings.Fields(text)
+			if len(fields) >= 3 && isCommit(fields[0]) {
+				line.Commit = fields[0]
+				number, _ = strconv.Atoi(fields[2])
+			}
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// isCommit reports whether the text is a full SHA-1 or SHA-256 commit hash.
+func isCommit(text string) bool {
+	if len(text) != 40 && len(text) != 64 {
+		return false
+	}
+	return strings.Trim(text, "0123456789abcdef") == ""
+}
+
+// CommitsSince returns the commits of HEAD which are not reachable from the ref.
+func CommitsSince(dir string, ref string) (map[string]struct{}, error) {
+	output, err := gitOutput(dir, "rev-list", ref+"..HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make(map[string]struct{})
+	for _, commit := range strings.Fields(string(output)) {
+		commits[commit] = struct{}{}
+	}
+	return commits, nil
+}
+
+func gitOutput(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// blamer annotates the records with git blame, every file is blamed once.
+type blamer struct {
+	dir   string
+	files map[string][]BlameLine
+	// failed are the files which cannot be blamed, the error is logged once.
+	failed map[string]error
+}
+
+func newBlamer(dir string) *blamer {
+	return &blamer{dir: dir, files: make(map[string][]BlameLine), failed: make(map[string]error)}
+}
+
+// annotate sets the author and the commit of the record line. It returns
+// the error only the first time the file cannot be blamed.
+func (b *blamer) annotate(record *ErrorRecord) error {
+	if record.Path == "" || record.Line <= 0 {
+		return nil
+	}
+	if _, failed := b.failed[record.Path]; failed {
+		return nil
+	}
+
+	lines, found := b.files[record.Path]
+	if !found {
+		var err error
+		if lines, err = Blame(b.dir, record.Path); err != nil {
+			b.failed[record.Path] = err
+			return err
+		}
+		b.files[record.Path] = lines
+	}
+	if record.Line > len(lines) {
+		return nil
+	}
+
+	line := lines[record.Line-1]
+	record.Commit = line.Commit
+	record.Author = line.Author
+	record.AuthorMail = line.AuthorMail
+	return nil
+}
@@ -0,0 +1,105 @@
+package converter
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+)
+
+// CheckstyleSink writes the report into <name>.checkstyle.xml, the records
+// are grouped by the module files, see Options.SourceDir. The records
+// without a file are grouped by the module names.
+type CheckstyleSink struct {
+	// PathPrefix is the path of Options.SourceDir in the repository, e.g. "src/".
+	PathPrefix string
+
+	// Logger receives debug messages, nil means slog.Default.
+	Logger *slog.Logger
+}
+
+type Checkstyle struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []CheckstyleFile `xml:"file"`
+}
+
+type CheckstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []CheckstyleError `xml:"error"`
+}
+
+type CheckstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// NewCheckstyle groups the records by the files in the order of the input,
+// the errors of a file are ordered by line.
+func NewCheckstyle(report Report, pathPrefix string) Checkstyle {
+	checkstyle := Checkstyle{Version: "8.0"}
+	groups := groupRecords(report.Records, func(record ErrorRecord) []string {
+		if record.Path == "" {
+			return []string{record.ErrorModule}
+		}
+		return []string{pathPrefix + record.Path}
+	})
+	for _, group := range groups {
+		file := CheckstyleFile{Name: group.key}
+		sort.SliceStable(group.records, func(i, j int) bool {
+			return group.records[i].Line < group.records[j].Line
+		})
+		for _, record := range group.records {
+			message := record.ErrorText
+			if record.Path == "" && record.ErrorLine != "" {
+				message = record.ErrorLine + ": " + message
+			}
+			message, _ = sanitizeText(message, "")
+			source, _ := sanitizeText(inspectionTypeID(record), "")
+			file.Errors = append(file.Errors, CheckstyleError{
+				Line:     record.Line,
+				Column:   record.Column,
+				Severity: record.Severity,
+				Message:  message,
+				Source:   source,
+			})
+		}
+		file.Name, _ = sanitizeText(file.Name, "")
+		checkstyle.Files = append(checkstyle.Files, file)
+	}
+	return checkstyle
+}
+
+func (s CheckstyleSink) Write(ctx context.Context, out Output, report Report) error {
+	checkstyle := NewCheckstyle(report, s.PathPrefix)
+	loggerOrDefault(s.Logger).Debug("writing checkstyle report", "name", report.Name, "files", len(checkstyle.Files))
+
+	return writeFile(out, report.Name+".checkstyle.xml", func(w io.Writer) error {
+		xmlData, err := xml.MarshalIndent(checkstyle, "", "    ")
+		if err != nil {
+			return fmt.Errorf("marshaling xml: %w", err)
+		}
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return fmt.Errorf("writing header: %w", err)
+		}
+		if _, err := w.Write(xmlData); err != nil {
+			return fmt.Errorf("writing xml data: %w", err)
+		}
+		return nil
+	})
+}
+
+func init() {
+	RegisterSink("checkstyle", func(options map[string]any) (Sink, error) {
+		var opts annotationOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return CheckstyleSink{PathPrefix: opts.PathPrefix}, nil
+	})
+}
@@ -0,0 +1,268 @@
+// Package converter converts EDT validation results into JUnit XML reports.
+//
+// The conversion is a pipeline: a Source reads error records, Filters skip
+// the records which are not reported and Sinks write the report. Sources,
+// filters and sinks are registered by name, so the configuration can refer
+// to them, see RegisterSource, RegisterFilter and RegisterSink.
+package converter
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Options control a single conversion.
+type Options struct {
+	// Name prefixes the test suite names, usually the input file name without extension.
+	Name string
+	// Timestamp is written to every test suite.
+	Timestamp string
+
+	SkipObjects                []string
+	SkipCategories             []string
+	SkipSignificanceCategories []string
+	SkipErrorText              []string
+
+	// Baseline holds the known errors which are skipped, see NewBaseline.
+	Baseline *Baseline
+
+	// Filters are applied in order after the skip lists and the baseline.
+	Filters []Filter
+
+	// Projects add rules for the records of the projects. When set,
+	// Process writes a separate report <Name>_<project> for every project.
+	Projects map[string]ProjectOptions
+
+	// SourceDir is the folder with the sources of the checked projects,
+	// the records get the paths of their modules, see ResolveModule.
+	SourceDir string
+	// Blame sets the author and the commit of the records from git blame,
+	// SourceDir must be in a git working copy.
+	Blame bool
+	// Severities map the significances to the severities of the records,
+	// in addition to DefaultSeverities.
+	Severities map[string]string
+	// Owners set the owners of the records.
+	Owners *Owners
+	// Changes keep only the records on the changed lines, see ParseDiff.
+	Changes *Changes
+	// GroupBy names the record field, see RecordField, every value of which
+	// is written as a separate report <Name>_<value>. Grouped by owner,
+	// the records are written to the report of every owner.
+	GroupBy string
+
+	// Logger receives debug messages, nil means slog.Default.
+	Logger *slog.Logger
+}
+
+func loggerOrDefault(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
+
+// filters returns the skip lists, the baseline and the changes as filters
+// followed by opts.Filters.
+func (o Options) filters() []Filter {
+	var result []Filter
+	if len(o.SkipObjects) > 0 {
+		result = append(result, SkipObjectsFilter(o.SkipObjects))
+	}
+	if len(o.SkipCategories) > 0 {
+		result = append(result, SkipCategoriesFilter(o.SkipCategories))
+	}
+	if len(o.SkipSignificanceCategories) > 0 {
+		result = append(result, SkipSignificanceCategoriesFilter(o.SkipSignificanceCategories))
+	}
+	if len(o.SkipErrorText) > 0 {
+		result = append(result, SkipErrorTextFilter(o.SkipErrorText))
+	}
+	if o.Baseline != nil {
+		result = append(result, BaselineFilter(o.Baseline))
+	}
+	if o.Changes != nil {
+		result = append(result, ChangesFilter(o.Changes))
+	}
+	return append(result, o.Filters...)
+}
+
+// Convert reads EDT validation results from r and writes the JUnit report to w.
+func Convert(ctx context.Context, r io.Reader, w io.Writer, opts Options) error {
+	return Run(ctx, TSVSource{}, r, WriterOutput{W: w}, []Sink{JUnitSink{ContextLines: DefaultContextLines, Logger: opts.Logger}}, opts)
+}
+
+// Run reads the records from r with the source and passes them to Process.
+func Run(ctx context.Context, source Source, r io.Reader, out Output, sinks []Sink, opts Options) error {
+	records, err := source.Read(ctx, r)
+	if err != nil {
+		return err
+	}
+	return Process(ctx, records, out, sinks, opts)
+}
+
+// Process filters the records and writes the report with every sink.
+// With Options.Projects every project is written as a separate report
+// with the project sinks and output, without records the empty report
+// is written as is.
+func Process(ctx context.Context, records []ErrorRecord, out Output, sinks []Sink, opts Options) error {
+	if len(opts.Projects) == 0 || len(records) == 0 {
+		return writeReport(ctx, records, out, sinks, opts)
+	}
+
+	for _, group := range groupByProject(records) {
+		projectOut, projectSinks := out, sinks
+		projectOpts := opts
+		projectOpts.Name = projectName(opts.Name, group.key)
+		if project, found := opts.Projects[group.key]; found {
+			if project.Output != nil {
+				projectOut = project.Output
+			}
+			if project.Sinks != nil {
+				projectSinks = project.Sinks
+			}
+			if project.GroupBy != "" {
+				projectOpts.GroupBy = project.GroupBy
+			}
+		}
+
+		if err := writeReport(ctx, group.records, projectOut, projectSinks, projectOpts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeReport(ctx context.Context, records []ErrorRecord, out Output, sinks []Sink, opts Options) error {
+	report, err := NewReport(ctx, records, opts)
+	if err != nil {
+		return err
+	}
+	// Without records there are no groups, the report is written as is,
+	// so that the checks expecting it find an empty report.
+	if opts.GroupBy == "" || len(report.Records) == 0 {
+		return writeSinks(ctx, out, sinks, report)
+	}
+
+	if _, err := RecordField(ErrorRecord{}, opts.GroupBy); err != nil {
+		return err
+	}
+	keys := func(record ErrorRecord) []string {
+		value, _ := RecordField(record, opts.GroupBy)
+		if opts.GroupBy == "owner" && value != "" {
+			return strings.Fields(value)
+		}
+		return []string{value}
+	}
+	skipped := make(map[string][]SkippedRecord)
+	for _, record := range report.Skipped {
+		for _, key := range keys(record.Record) {
+			skipped[key] = append(skipped[key], record)
+		}
+	}
+
+	for _, group := range groupRecords(report.Records, keys) {
+		groupReport := report
+		groupReport.Name = projectName(report.Name, fileNamePart(group.key))
+		groupReport.Records = group.records
+		groupReport.Skipped = skipped[group.key]
+		if err := writeSinks(ctx, out, sinks, groupReport); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSinks(ctx context.Context, out Output, sinks []Sink, report Report) error {
+	for _, sink := range sinks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := sink.Write(ctx, out, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewReport keeps the records which are not skipped by any filter,
+// including the filters of the record project, the skipped records are
+// kept in Report.Skipped. Records without
+// a source get the report name as the source, records without a path
+// get the module path when Options.SourceDir is set, see also Options.Blame.
+func NewReport(ctx context.Context, records []ErrorRecord, opts Options) (Report, error) {
+	logger := loggerOrDefault(opts.Logger)
+	filters := opts.filters()
+	projectFilters := make(map[string][]Filter)
+	for name, project := range opts.Projects {
+		projectFilters[name] = append(append([]Filter{}, filters...), project.filters()...)
+	}
+
+	report := Report{
+		Name:      opts.Name,
+		Timestamp: opts.Timestamp,
+		SourceDir: opts.SourceDir,
+	}
+	modulePaths := make(map[[2]string]string)
+	var blame *blamer
+	if opts.Blame && opts.SourceDir != "" {
+		blame = newBlamer(opts.SourceDir)
+	}
+
+records:
+	for _, record := range records {
+		if err := ctx.Err(); err != nil {
+			return Report{}, err
+		}
+
+		if record.Source == "" {
+			record.Source = opts.Name
+		}
+		if record.Path == "" && opts.SourceDir != "" {
+			module := [2]string{record.Project, record.ErrorModule}
+			modulePath, found := modulePaths[module]
+			if !found {
+				modulePath = ResolveModule(opts.SourceDir, record)
+				modulePaths[module] = modulePath
+			}
+			record.Path = modulePath
+		}
+		if blame != nil {
+			if err := blame.annotate(&record); err != nil {
+				logger.Warn("failed git blame", "path", record.Path, "error", err.Error())
+			}
+		}
+		if record.Severity == "" {
+			record.Severity = opts.severity(record.Priority)
+		}
+		if record.Owner == "" && opts.Owners != nil {
+			record.Owner = strings.Join(opts.Owners.Of(record), " ")
+		}
+
+		recordFilters := filters
+		if project, found := projectFilters[record.Project]; found {
+			recordFilters = project
+		}
+		for _, filter := range recordFilters {
+			if filter.Skip(record) {
+				logger.Debug("record skipped", "filter", filter.Name(), "record", record)
+				report.Skipped = append(report.Skipped, SkippedRecord{Record: record, Filter: filter.Name()})
+				continue records
+			}
+		}
+		report.Records = append(report.Records, record)
+	}
+	return report, nil
+}
+
+// BuildTestSuites groups the records that are not skipped into test suites,
+// see NewTestSuites.
+func BuildTestSuites(ctx context.Context, records []ErrorRecord, opts Options) (TestSuites, error) {
+	report, err := NewReport(ctx, records, opts)
+	if err != nil {
+		return TestSuites{}, err
+	}
+	return NewTestSuites(report, loggerOrDefault(opts.Logger)), nil
+}
@@ -0,0 +1,972 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestGetTestSuiteByName_WhenTestSuitesIsEmpty(t *testing.T) {
+	testSuites := TestSuites{
+		XMLName:   xml.Name{},
+		Time:      "0",
+		Tests:     0,
+		Errors:    0,
+		Failures:  0,
+		TestSuite: []TestSuite{},
+	}
+	testSuiteTimestamp := "2023-01-01T12:00:00"
+	fileName := "test_file"
+	recordName := "test_record"
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	expectedTestSuite := TestSuite{
+		Name:       fileName + "_" + recordName,
+		Timestamp:  testSuiteTimestamp,
+		Time:       "0",
+		Tests:      0,
+		Errors:     0,
+		Failures:   0,
+		Skipped:    0,
+		Properties: []Property{},
+		TestCases:  []TestCase{},
+	}
+	expectedIndex := -1
+
+	actualTestSuite, actualIndex := getTestSuiteByName(testSuites, testSuiteTimestamp, fileName, recordName, *logger)
+
+	if !reflect.DeepEqual(actualTestSuite, expectedTestSuite) {
+		t.Errorf("expected test suite: %v, got: %v", expectedTestSuite, actualTestSuite)
+	}
+	if actualIndex != expectedIndex {
+		t.Errorf("expected index: %d, got: %d", expectedIndex, actualIndex)
+	}
+}
+
+func TestGetTestSuiteByName_ExistingTestSuite(t *testing.T) {
+	testSuites := TestSuites{
+		Time:     "0",
+		Tests:    0,
+		Errors:   0,
+		Failures: 0,
+		TestSuite: []TestSuite{
+			{
+				Name:       "filename_recordname",
+				Timestamp:  "2022-01-01T12:00:00",
+				Time:       "0",
+				Tests:      10,
+				Errors:     0,
+				Failures:   0,
+				Skipped:    0,
+				Properties: []Property{},
+				TestCases:  []TestCase{},
+			},
+		},
+	}
+	testSuiteTimestamp := "2022-01-01T12:00:00"
+	fileName := "filename"
+	recordName := "recordname"
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	resultTestSuite, _ := getTestSuiteByName(testSuites, testSuiteTimestamp, fileName, recordName, *logger)
+
+	expectedTestSuite := TestSuite{
+		Name:       "filename_recordname",
+		Timestamp:  "2022-01-01T12:00:00",
+		Time:       "0",
+		Tests:      10,
+		Errors:     0,
+		Failures:   0,
+		Skipped:    0,
+		Properties: []Property{},
+		TestCases:  []TestCase{},
+	}
+	assert.Equal(t, expectedTestSuite, resultTestSuite, "gettestsuitebyname should return an existing test suite")
+}
+
+func TestGetTestSuiteByName_NotExistingTestSuite(t *testing.T) {
+	testSuites := TestSuites{
+		Time:     "0",
+		Tests:    0,
+		Errors:   0,
+		Failures: 0,
+		TestSuite: []TestSuite{
+			{
+				Name:       "filename_recordname_1",
+				Timestamp:  "2022-01-01T12:00:00",
+				Time:       "0",
+				Tests:      10,
+				Errors:     0,
+				Failures:   0,
+				Skipped:    0,
+				Properties: []Property{},
+				TestCases:  []TestCase{},
+			},
+		},
+	}
+	testSuiteTimestamp := "2022-01-01T12:00:00"
+	fileName := "filename"
+	recordName := "recordname"
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	resultTestSuite, _ := getTestSuiteByName(testSuites, testSuiteTimestamp, fileName, recordName, *logger)
+
+	expectedTestSuite := TestSuite{
+		Name:       "filename_recordname",
+		Timestamp:  "2022-01-01T12:00:00",
+		Time:       "0",
+		Tests:      0,
+		Errors:     0,
+		Failures:   0,
+		Skipped:    0,
+		Properties: []Property{},
+		TestCases:  []TestCase{},
+	}
+	assert.Equal(t, expectedTestSuite, resultTestSuite, "gettestsuitebyname should return an existing test suite")
+}
+
+func TestGetTestCaseByName_ExistingTestCase(t *testing.T) {
+	testSuite := TestSuite{
+		TestCases: []TestCase{
+			{
+				Name: "TestCase1",
+			},
+			{
+				Name: "TestCase2",
+			},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	testCaseName := "TestCase2"
+
+	testCase, index := getTestCaseByName(testSuite, testCaseName, *logger)
+
+	if index != 1 {
+		t.Errorf("Expected index 1, but got %d", index)
+	}
+	if testCase.Name != testCaseName {
+		t.Errorf("Expected testCaseName %s, but got %s", testCaseName, testCase.Name)
+	}
+}
+
+func TestGetTestCaseByName_CreatesNewTestCase_WhenNameDoesNotMatch(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	testSuite := TestSuite{
+		TestCases: []TestCase{
+			{Name: "TestCase1"},
+			{Name: "TestCase2"},
+		},
+	}
+	testCaseName := "TestCase3"
+
+	testCase, _ := getTestCaseByName(testSuite, testCaseName, *logger)
+
+	if testCase.Name != testCaseName {
+		t.Errorf("Expected testCase.Name to be %s, but got %s", testCaseName, testCase.Name)
+	}
+}
+
+func TestGetTestCaseByName_EmptyTestCaseName(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	testSuite := TestSuite{
+		TestCases: []TestCase{},
+	}
+	testCaseName := ""
+
+	testCase, index := getTestCaseByName(testSuite, testCaseName, *logger)
+
+	if index != -1 {
+		t.Errorf("Expected index to be -1, but got %d", index)
+	}
+	if testCase.Name != "" {
+		t.Errorf("Expected empty testCase.Name, but got %s", testCase.Name)
+	}
+}
+
+func TestRecordInSkipCategory(t *testing.T) {
+	skipCategories := []string{"Предупреждение"}
+
+	tests := []struct {
+		input  []string
+		output bool
+	}{
+		{input: []string{"Предупреждение", "Предупреждение", "Предупреждение"}, output: true},
+		{input: []string{"A1", "B2", "B2"}, output: false},
+	}
+
+	for _, tt := range tests {
+		result := recordInSkipCategory(NewErrorRecord(tt.input), skipCategories)
+		if result != tt.output {
+			t.Errorf("recordInSkipCategory(%q) = %v, want %v", tt.input, result, tt.output)
+		}
+	}
+}
+
+func TestRecordInSkipObject(t *testing.T) {
+	skipObjects := []string{"Справочник.Номенклатура.МодульОбъекта", ".Удалить"}
+
+	tests := []struct {
+		input  []string
+		output bool
+	}{
+		{input: []string{"A1", "A1", "A1", "A1", "A1", "A1"}, output: false},
+		{input: []string{"A1", "A1", "A1", "A1", "A1", "Справочник.Номенклатура.МодульОбъекта"}, output: true},
+		{input: []string{"A1", "A1", "A1", "A1", "A1", "Справочник.Удалить_Номенклатура.МодульОбъекта"}, output: true},
+		{input: []string{"A1", "A1", "A1", "A1", "A1", "Справочник.УдалитьНоменклатура.МодульОбъекта"}, output: true},
+	}
+
+	for _, tt := range tests {
+		result := recordInSkipObject(NewErrorRecord(tt.input), skipObjects)
+		if result != tt.output {
+			t.Errorf("recordInSkipObject(%q) = %v, want %v", tt.input, result, tt.output)
+		}
+	}
+}
+
+func TestRecordInSkipList(t *testing.T) {
+	skipCategories := []string{"Предупреждение"}
+	skipObjects := []string{"Справочник.Номенклатура.МодульОбъекта", ".Удалить"}
+	skipSignificanteCategories := []string{"Значительная_Переносимость"}
+	skipErrorText := []string{"Неподдерживаемый оператор [Web-клиент]"}
+
+	tests := []struct {
+		input  []string
+		output bool
+	}{
+		{input: []string{"A1", "A1", "A1", "A1", "A1", "A1", "", ""}, output: false},
+		{input: []string{"A1", "A1", "A1", "A1", "A1", "A1", "", "Неподдерживаемый оператор [Web-клиент]"}, output: true},
+		{input: []string{"A1", "A1", "Предупреждение", "A1", "A1", "A1", "", ""}, output: true},
+		{input: []string{"A1", "Значительная", "Переносимость", "A1", "A1", "A1", "", ""}, output: true},
+		{input: []string{"A1", "A1", "A1", "A1", "A1", "Справочник.Номенклатура.МодульОбъекта", "", ""}, output: true},
+		{input: []string{"A1", "A1", "A1", "A1", "A1", "Справочник.Удалить_Номенклатура.МодульОбъекта", "", ""}, output: true},
+		{input: []string{"A1", "A1", "A1", "A1", "A1", "Справочник.УдалитьНоменклатура.МодульОбъекта", "", ""}, output: true},
+	}
+
+	for _, tt := range tests {
+		result := recordInSkipList(NewErrorRecord(tt.input), skipObjects, skipCategories, skipSignificanteCategories, skipErrorText)
+		if result != tt.output {
+			t.Errorf("recordInSkipList(%q) = %v, want %v", tt.input, result, tt.output)
+		}
+	}
+}
+
+func TestRecordInSkipErrorsList(t *testing.T) {
+	record := []string{"A1", "A1", "A1", "A1", "A1", "A1", "A1", "A1"}
+	key := strings.Join(append(record[1:2], record[3:]...), "\t")
+	parentErrorsKeys := make(map[string]struct{})
+	parentErrorsKeys[key] = struct{}{}
+
+	tests := []struct {
+		input  []string
+		output bool
+	}{
+		{input: []string{"A1", "A1", "A1", "A1", "A1", "A1", "A1", "A1"}, output: true},
+		{input: []string{"A1", "A2", "A1", "A1", "A1", "A1", "A1", "A1"}, output: false},
+	}
+
+	for _, tt := range tests {
+		result := recordInSkipErrorsList(NewErrorRecord(tt.input), parentErrorsKeys)
+		if result != tt.output {
+			t.Errorf("recordInSkipErrorsList(%q) = %v, want %v", tt.input, result, tt.output)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	input := "2024-07-17T15:04:48+0300\tОшибка конфигурации\t\tcf\t\tОбработка.Тест.Форма.Модуль\tстрока 1036\tФункция не определена\n" +
+		"2024-07-17T15:04:48+0300\tТривиальная\tПредупреждение\tcf\t\tОбработка.Тест.Форма.Модуль\tстрока 1\tПропускается\n"
+	var output strings.Builder
+
+	err := Convert(context.Background(), strings.NewReader(input), &output, Options{
+		Name:           "src",
+		Timestamp:      "2025-02-18T15:07:51",
+		SkipCategories: []string{"Предупреждение"},
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, output.String(), `<testsuites time="0" tests="1" errors="0" failures="1">`)
+	assert.Contains(t, output.String(), `<testsuite name="src_Ошибка конфигурации_" timestamp="2025-02-18T15:07:51"`)
+	assert.Contains(t, output.String(), `<failure message="Ошибка конфигурации; ; " type="">Обработка.Тест.Форма.Модуль; строка 1036; Функция не определена</failure>`)
+	assert.NotContains(t, output.String(), "Пропускается")
+}
+
+func TestConvert_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Convert(ctx, strings.NewReader("a\tb\tc\n"), io.Discard, Options{})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewFilter(t *testing.T) {
+	filter, err := NewFilter("skip_categories", map[string]any{"values": []any{"Предупреждение"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "skip_categories", filter.Name())
+	assert.True(t, filter.Skip(ErrorRecord{CheckType: "Предупреждение"}))
+	assert.False(t, filter.Skip(ErrorRecord{CheckType: "Ошибка"}))
+
+	_, err = NewFilter("skip_categories", map[string]any{"value": []any{"Предупреждение"}})
+	assert.Error(t, err, "unknown option should be rejected")
+
+	_, err = NewFilter("unknown", nil)
+	assert.Error(t, err)
+}
+
+func TestSkipRegexpFilter(t *testing.T) {
+	filter, err := NewFilter("skip_regexp", map[string]any{"field": "error_module", "values": []any{`^Справочник\.Удалить`}})
+
+	assert.NoError(t, err)
+	assert.True(t, filter.Skip(ErrorRecord{ErrorModule: "Справочник.УдалитьНоменклатура.МодульОбъекта"}))
+	assert.False(t, filter.Skip(ErrorRecord{ErrorModule: "Справочник.Номенклатура.МодульОбъекта"}))
+
+	_, err = NewFilter("skip_regexp", map[string]any{"values": []any{"("}})
+	assert.Error(t, err)
+
+	_, err = NewFilter("skip_regexp", map[string]any{"field": "module"})
+	assert.Error(t, err)
+}
+
+// memoryOutput keeps the written files in memory.
+type memoryOutput map[string]*strings.Builder
+
+func (m memoryOutput) Create(name string) (io.WriteCloser, error) {
+	m[name] = &strings.Builder{}
+	return nopCloser{m[name]}, nil
+}
+
+func TestProcess_Projects(t *testing.T) {
+	records := []ErrorRecord{
+		{Priority: "Критическая", CheckType: "Ошибка", Project: "cf", ErrorModule: "ОбщийМодуль.А.Модуль", ErrorText: "a"},
+		{Priority: "Критическая", CheckType: "Производительность", Project: "Расширение", ErrorModule: "ОбщийМодуль.Б.Модуль", ErrorText: "b"},
+		{Priority: "Критическая", CheckType: "Ошибка", Project: "Расширение", ErrorModule: "ОбщийМодуль.В.Модуль", ErrorText: "c"},
+		{Priority: "Критическая", CheckType: "Производительность", Project: "cf", ErrorModule: "ОбщийМодуль.Г.Модуль", ErrorText: "d"},
+	}
+	out := memoryOutput{}
+	extensionOut := memoryOutput{}
+
+	err := Process(context.Background(), records, out, []Sink{JUnitSink{}}, Options{
+		Name: "src",
+		Projects: map[string]ProjectOptions{
+			"Расширение": {SkipCategories: []string{"Производительность"}, Output: extensionOut},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, out, 1)
+	assert.Contains(t, out["src_cf.xml"].String(), `tests="2"`)
+	assert.Len(t, extensionOut, 1)
+	assert.Contains(t, extensionOut["src_Расширение.xml"].String(), `<testsuites time="0" tests="1"`)
+	assert.Contains(t, extensionOut["src_Расширение.xml"].String(), "ОбщийМодуль.В.Модуль")
+}
+
+func TestProcess_ProjectsWithoutRecords(t *testing.T) {
+	out := memoryOutput{}
+
+	err := Process(context.Background(), nil, out, []Sink{JUnitSink{}}, Options{Name: "empty", Projects: map[string]ProjectOptions{"cf": {}}})
+
+	assert.NoError(t, err)
+	assert.Len(t, out, 1)
+	assert.Contains(t, out["empty.xml"].String(), `tests="0"`)
+}
+
+func TestProcess_ProjectGroupBy(t *testing.T) {
+	records := []ErrorRecord{
+		{Priority: "Критическая", CheckType: "Ошибка", Project: "cf", ErrorModule: "ОбщийМодуль.А.Модуль", ErrorText: "a"},
+		{Priority: "Критическая", CheckType: "Производительность", Project: "cf", ErrorModule: "ОбщийМодуль.Б.Модуль", ErrorText: "b"},
+		{Priority: "Критическая", CheckType: "Ошибка", Project: "Расширение", ErrorModule: "ОбщийМодуль.В.Модуль", ErrorText: "c"},
+	}
+	out := memoryOutput{}
+
+	err := Process(context.Background(), records, out, []Sink{JUnitSink{}}, Options{
+		Name:     "src",
+		Projects: map[string]ProjectOptions{"cf": {GroupBy: "check_type"}},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, out, 3)
+	assert.Contains(t, out["src_cf_Ошибка.xml"].String(), "ОбщийМодуль.А.Модуль")
+	assert.Contains(t, out["src_cf_Производительность.xml"].String(), "ОбщийМодуль.Б.Модуль")
+	assert.Contains(t, out["src_Расширение.xml"].String(), "ОбщийМодуль.В.Модуль")
+}
+
+func TestNewTestSuites_NamedBySource(t *testing.T) {
+	report := Report{
+		Name: "merged",
+		Records: []ErrorRecord{
+			{Priority: "Критическая", CheckType: "Ошибка", ErrorModule: "А", Source: "cf/a"},
+			{Priority: "Критическая", CheckType: "Ошибка", ErrorModule: "Б", Source: "ext/b"},
+			{Priority: "Критическая", CheckType: "Ошибка", ErrorModule: "В"},
+		},
+	}
+
+	testSuites := NewTestSuites(report, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	assert.Equal(t, 3, testSuites.Tests)
+	assert.Equal(t, "cf/a_Критическая_Ошибка", testSuites.TestSuite[0].Name)
+	assert.Equal(t, "ext/b_Критическая_Ошибка", testSuites.TestSuite[1].Name)
+	assert.Equal(t, "merged_Критическая_Ошибка", testSuites.TestSuite[2].Name)
+}
+
+func TestReadTSV_Encodings(t *testing.T) {
+	line := "2024-07-17T15:04:48+0300\tОшибка\tКатегория\tcf\t\tОбщийМодуль.А.Модуль\tстрока 1\tТекст\n"
+	utf16le, _ := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String(line)
+	utf16leNoBOM, _ := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder().String(line)
+	utf16be, _ := unicode.UTF16(unicode.BigEndian, unicode.UseBOM).NewEncoder().String(line)
+	cp1251, _ := charmap.Windows1251.NewEncoder().String(line)
+
+	inputs := map[string]string{
+		"utf-8":           line,
+		"utf-8 bom":       "\xEF\xBB\xBF" + line,
+		"utf-16le":        utf16le,
+		"utf-16le no bom": utf16leNoBOM,
+		"utf-16be":        utf16be,
+		"windows-1251":    cp1251,
+	}
+	for name, input := range inputs {
+		records, err := ReadTSV(strings.NewReader(input))
+
+		assert.NoError(t, err, name)
+		assert.Equal(t, []ErrorRecord{NewErrorRecord(strings.Split(strings.TrimSuffix(line, "\n"), "\t"))}, records, name)
+	}
+
+	records, err := TSVSource{Encoding: EncodingWindows1251}.Read(context.Background(), strings.NewReader(cp1251))
+	assert.NoError(t, err)
+	assert.Equal(t, "Текст", records[0].ErrorText)
+
+	_, err = NewSource("edt_tsv", map[string]any{"encoding": "koi8"})
+	assert.Error(t, err)
+}
+
+func TestJUnitSink_SanitizesAndTruncates(t *testing.T) {
+	report := Report{
+		Name: "src",
+		Records: []ErrorRecord{
+			{Priority: "Ошибка", ErrorModule: "Модуль\x00", ErrorLine: "строка 1", ErrorText: "Текст\x01\x1b[0m\xff�" + strings.Repeat("я", 100),
+				Path: "Модуль\x02.bsl", Owner: "@org/core", Commit: "abc", Author: "Иван\x1b[1m", AuthorMail: "ivan@example.com"},
+		},
+	}
+	out := memoryOutput{}
+
+	err := JUnitSink{Replacement: "?", MaxFailureLength: 40}.Write(context.Background(), out, report)
+
+	assert.NoError(t, err)
+	xmlData := out["src.xml"].String()
+	assert.Contains(t, xmlData, `name="Модуль?"`)
+	assert.Contains(t, xmlData, ">Модуль?; стр… (119 characters truncated)</failure>")
+	assert.Contains(t, xmlData, `file="Модуль?.bsl"`)
+	assert.Contains(t, xmlData, `<property name="author" value="Иван?[1m"></property>`)
+
+	var testSuites TestSuites
+	assert.NoError(t, xml.Unmarshal([]byte(strings.TrimPrefix(xmlData, xml.Header)), &testSuites))
+	assert.Equal(t, 40, utf8.RuneCountInString(testSuites.TestSuite[0].TestCases[0].Failures[0].Text))
+	assert.Equal(t, "абв", truncateText("абвгд", 3), "the note does not fit")
+}
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		input  string
+		line   int
+		column int
+	}{
+		{"строка 1036", 1036, 0},
+		{"Строка 12, столбец 5", 12, 5},
+		{"line 7", 7, 0},
+		{"Line 7, column 14", 7, 14},
+		{"42", 42, 0},
+		{"", 0, 0},
+		{"модуль", 0, 0},
+	}
+
+	for _, tt := range tests {
+		line, column := ParseLine(tt.input)
+		assert.Equal(t, tt.line, line, tt.input)
+		assert.Equal(t, tt.column, column, tt.input)
+	}
+}
+
+func TestBaseline_LineTolerance(t *testing.T) {
+	known := NewErrorRecord([]string{"", "Ошибка", "", "cf", "", "Модуль", "строка 10", "Текст"})
+	moved := NewErrorRecord([]string{"", "Ошибка", "", "cf", "", "Модуль", "строка 13", "Текст"})
+	far := NewErrorRecord([]string{"", "Ошибка", "", "cf", "", "Модуль", "строка 20", "Текст"})
+
+	exact := NewBaseline([]ErrorRecord{known}, 0)
+	assert.True(t, exact.Contains(known))
+	assert.False(t, exact.Contains(moved))
+
+	tolerant := NewBaseline([]ErrorRecord{known}, 3)
+	assert.True(t, tolerant.Contains(moved))
+	assert.False(t, tolerant.Contains(far))
+}
+
+func TestNewTestSuites_SortedByLine(t *testing.T) {
+	report := Report{
+		Name: "report",
+		Records: []ErrorRecord{
+			NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "Модуль", "строка 20", "Второй"}),
+			NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "Модуль", "строка 3", "Первый"}),
+		},
+	}
+
+	testSuites := NewTestSuites(report, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	testCases := testSuites.TestSuite[0].TestCases
+	assert.Len(t, testCases, 2)
+	assert.Equal(t, 3, testCases[0].Line)
+	assert.Equal(t, "Модуль; строка 3; Первый", testCases[0].Failures[0].Text)
+	assert.Equal(t, 20, testCases[1].Line)
+}
+
+func TestModulePath(t *testing.T) {
+	tests := []struct {
+		module string
+		path   string
+	}{
+		{"ОбщийМодуль.Б.Модуль", "CommonModules/Б/Module.bsl"},
+		{"Справочник.Валюты.МодульОбъекта", "Catalogs/Валюты/ObjectModule.bsl"},
+		{"Обработка.ОбменСПорталомСТТ.Форма.ФормаОбработки.Форма.Модуль", "DataProcessors/ОбменСПорталомСТТ/Forms/ФормаОбработки/Module.bsl"},
+		{"Документ.Заказ.Команда.Печать.МодульКоманды", "Documents/Заказ/Commands/Печать/CommandModule.bsl"},
+		{"ОбщаяФорма.Вопрос.Форма.Модуль", "CommonForms/Вопрос/Module.bsl"},
+		{"Конфигурация.МодульСеанса", "Configuration/SessionModule.bsl"},
+		{"CommonModule.Common.Module", "CommonModules/Common/Module.bsl"},
+		{"Справочник.Валюты", ""},
+		{"Подсистема.Администрирование.Модуль", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.path, ModulePath(tt.module), tt.module)
+	}
+}
+
+func TestJUnitSink_Snippets(t *testing.T) {
+	dir := t.TempDir()
+	modulePath := filepath.Join(dir, "cf", "src", "CommonModules", "Б", "Module.bsl")
+	if err := os.MkdirAll(filepath.Dir(modulePath), 0777); err != nil {
+		t.Fatal(err)
+	}
+	code := "\ufeffПроцедура А()\r\n\tБ = 1;\r\n\tВ();\r\n\tГ = 2;\r\nКонецПроцедуры\r\n"
+	if err := os.WriteFile(modulePath, []byte(code), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	records := []ErrorRecord{
+		NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "ОбщийМодуль.Б.Модуль", "строка 3", "Процедура не определена"}),
+	}
+	out := memoryOutput{}
+	err := Process(context.Background(), records, out, []Sink{JUnitSink{ContextLines: 1}}, Options{Name: "report", SourceDir: dir})
+
+	assert.NoError(t, err)
+	var testSuites TestSuites
+	assert.NoError(t, xml.Unmarshal([]byte(out["report.xml"].String()), &testSuites))
+	testCase := testSuites.TestSuite[0].TestCases[0]
+	assert.Equal(t, "cf/src/CommonModules/Б/Module.bsl", testCase.File)
+	assert.Equal(t, 3, testCase.Line)
+	assert.Equal(t, "ОбщийМодуль.Б.Модуль; строка 3; Процедура не определена\n\n  2 | \tБ = 1;\n> 3 | \tВ();\n  4 | \tГ = 2;", testCase.Failures[0].Text)
+}
+
+func TestProcess_BlameGroupByAuthor(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	git := func(author string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME="+author, "GIT_AUTHOR_EMAIL="+author+"@example.com",
+			"GIT_COMMITTER_NAME="+author, "GIT_COMMITTER_EMAIL="+author+"@example.com")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, output)
+		}
+	}
+	modulePath := filepath.Join(dir, "src", "CommonModules", "Б", "Module.bsl")
+	writeModule := func(code string) {
+		if err := os.MkdirAll(filepath.Dir(modulePath), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(modulePath, []byte(code), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("anna", "init", "-q")
+	writeModule("А();\nБ();\n")
+	git("anna", "add", ".")
+	git("anna", "commit", "-q", "-m", "first")
+	git("anna", "tag", "base")
+	writeModule("А();\nБ();\nВ();\n")
+	git("boris", "commit", "-q", "-am", "second")
+
+	records := []ErrorRecord{
+		NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "ОбщийМодуль.Б.Модуль", "строка 1", "Первая"}),
+		NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "ОбщийМодуль.Б.Модуль", "строка 3", "Вторая"}),
+	}
+	out := memoryOutput{}
+	err := Process(context.Background(), records, out, []Sink{JUnitSink{ContextLines: -1}}, Options{Name: "report", SourceDir: dir, Blame: true, GroupBy: "author"})
+
+	assert.NoError(t, err)
+	assert.Len(t, out, 2)
+	assert.Contains(t, out["report_anna.xml"].String(), `<property name="author_mail" value="anna@example.com"></property>`)
+	assert.Contains(t, out["report_boris.xml"].String(), "Вторая")
+
+	commits, err := CommitsSince(dir, "base")
+	assert.NoError(t, err)
+	report, err := NewReport(context.Background(), records, Options{SourceDir: dir, Blame: true, Filters: []Filter{BlameSinceFilter(commits)}})
+	assert.NoError(t, err)
+	assert.Len(t, report.Records, 1)
+	assert.Equal(t, "boris", report.Records[0].Author)
+
+	changes, err := GitDiff(dir, "base")
+	assert.NoError(t, err)
+	assert.True(t, changes.Contains("CommonModules/Б/Module.bsl", 3))
+	assert.False(t, changes.Contains("CommonModules/Б/Module.bsl", 1))
+}
+
+func TestParseDiff(t *testing.T) {
+	diff := `diff --git "a/cf/src/CommonModules/\320\221/Module.bsl" "b/cf/src/CommonModules/\320\221/Module.bsl"
+--- "a/cf/src/CommonModules/\320\221/Module.bsl"
++++ "b/cf/src/CommonModules/\320\221/Module.bsl"
+@@ -3,0 +4,2 @@ Процедура А()
++	В();
++	Г();
+@@ -10 +12 @@
+-	Д();
++	Е();
+@@ -20,2 +21,0 @@
+-	Ж();
+-	З();
+diff --git a/old.bsl b/old.bsl
+deleted file mode 100644
+--- a/old.bsl
++++ /dev/null
+@@ -1 +0,0 @@
+-А();
+`
+
+	changes, err := ParseDiff(strings.NewReader(diff))
+
+	assert.NoError(t, err)
+	path := "src/CommonModules/Б/Module.bsl"
+	assert.False(t, changes.Contains(path, 3))
+	assert.True(t, changes.Contains(path, 4))
+	assert.True(t, changes.Contains(path, 5))
+	assert.True(t, changes.Contains(path, 12))
+	assert.False(t, changes.Contains(path, 21))
+	assert.False(t, changes.Contains("CommonModules/Б/Module.bsl2", 4))
+	assert.False(t, changes.Contains("old.bsl", 1))
+
+	filter := ChangesFilter(changes)
+	assert.False(t, filter.Skip(ErrorRecord{Path: path, Line: 4}))
+	assert.True(t, filter.Skip(ErrorRecord{ErrorModule: "ОбщийМодуль.Б.Модуль", Line: 4}))
+}
+
+func TestProcess_OwnersGroupByOwner(t *testing.T) {
+	owners, err := ParseOwners(strings.NewReader(`
+# fallback
+*                         @org/core
+Справочник.*              @org/nsi
+/cf/src/CommonModules/    @org/platform @org/core
+*ObjectModule.bsl         @org/objects
+ОбщийМодуль.Старый*
+`))
+	assert.NoError(t, err)
+
+	records := []ErrorRecord{
+		{ErrorModule: "Справочник.Валюты.МодульМенеджера", Path: "cf/src/Catalogs/Валюты/ManagerModule.bsl"},
+		{ErrorModule: "ОбщийМодуль.Б.Модуль", Path: "cf/src/CommonModules/Б/Module.bsl"},
+		{ErrorModule: "Документ.Заказ.МодульОбъекта", Path: "cf/src/Documents/Заказ/ObjectModule.bsl"},
+		{ErrorModule: "ОбщийМодуль.СтарыйМодуль.Модуль"},
+		{ErrorModule: "Обработка.Загрузка", Path: "cf/src/DataProcessors/Загрузка/Загрузка.mdo"},
+		{ErrorModule: "Документ.Заказ.МодульОбъекта"},
+	}
+	assert.Equal(t, []string{"@org/nsi"}, owners.Of(records[0]))
+	assert.Equal(t, []string{"@org/platform", "@org/core"}, owners.Of(records[1]))
+	assert.Equal(t, []string{"@org/objects"}, owners.Of(records[2]))
+	assert.Empty(t, owners.Of(records[3]))
+	assert.Equal(t, []string{"@org/core"}, owners.Of(records[4]))
+	assert.Equal(t, []string{"@org/core"}, owners.Of(records[5]), "catch-all without the path")
+
+	out := memoryOutput{}
+	err = Process(context.Background(), records, out, []Sink{JUnitSink{}}, Options{Name: "report", Owners: owners, GroupBy: "owner"})
+
+	assert.NoError(t, err)
+	assert.Len(t, out, 5)
+	assert.Contains(t, out["report_@org_core.xml"].String(), "ОбщийМодуль.Б.Модуль")
+	assert.Contains(t, out["report_@org_core.xml"].String(), `<property name="owner" value="@org/platform @org/core"></property>`)
+	assert.Contains(t, out["report_@org_platform.xml"].String(), "ОбщийМодуль.Б.Модуль")
+	assert.Contains(t, out["report.xml"].String(), "ОбщийМодуль.СтарыйМодуль.Модуль")
+
+	report, err := NewReport(context.Background(), records, Options{Owners: owners, Filters: []Filter{OwnersFilter([]string{"@org/nsi"})}})
+	assert.NoError(t, err)
+	assert.Len(t, report.Records, 1)
+}
+
+func TestProcess_GroupByWithoutRecords(t *testing.T) {
+	records := []ErrorRecord{
+		NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "ОбщийМодуль.Б.Модуль", "строка 1", "Пропущена"}),
+	}
+	out := memoryOutput{}
+
+	err := Process(context.Background(), records, out, []Sink{JUnitSink{}, JSONSink{}}, Options{Name: "report", SkipCategories: []string{"Синтаксис"}, GroupBy: "project"})
+
+	assert.NoError(t, err)
+	assert.Len(t, out, 2)
+	assert.Contains(t, out["report.xml"].String(), `tests="0"`)
+	assert.Contains(t, out["report.json"].String(), `"reason": "skip_categories"`)
+}
+
+func TestHTMLSink(t *testing.T) {
+	known := NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "Справочник.Валюты.МодульОбъекта", "строка 3", "Известная"})
+	records := []ErrorRecord{
+		known,
+		NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "Справочник.Валюты.МодульМенеджера", "строка 7", "Новая <b>"}),
+		NewErrorRecord([]string{"", "Предупреждение", "Стандарты", "cf", "", "ОбщийМодуль.Б.Модуль", "строка 1", "Пропущена"}),
+	}
+	opts := Options{
+		Name:           "report",
+		SkipCategories: []string{"Стандарты"},
+		Baseline:       NewBaseline([]ErrorRecord{known}, 0),
+	}
+	out := memoryOutput{}
+
+	err := Process(context.Background(), records, out, []Sink{HTMLSink{}}, opts)
+
+	assert.NoError(t, err)
+	text := out["report.html"].String()
+	assert.Contains(t, text, "Ошибок: 1, пропущено фильтрами: 2, из них известных (baseline): 1.")
+	assert.Contains(t, text, `<tr><td>Справочник.Валюты</td><td class="number">1</td></tr>`)
+	assert.Contains(t, text, `<tr><td>skip_categories</td><td class="number">1</td></tr>`)
+	assert.Contains(t, text, `<td class="number">7</td><td>Новая &lt;b&gt;</td>`)
+	assert.NotContains(t, text, "Известная")
+}
+
+func TestMarkdownSink(t *testing.T) {
+	known := NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "ОбщийМодуль.А.Модуль", "строка 1", "Известная"})
+	records := []ErrorRecord{
+		known,
+		NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "ОбщийМодуль.Б.Модуль", "строка 3", "Первая"}),
+		NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "ОбщийМодуль.В.Модуль", "строка 5", "Вторая *важная*"}),
+		NewErrorRecord([]string{"", "Ошибка", "Синтаксис", "cf", "", "ОбщийМодуль.В.Модуль", "строка 9", "Третья"}),
+	}
+	records[1].Path = "cf/src/CommonModules/Б/Module.bsl"
+	opts := Options{Name: "report", Baseline: NewBaseline([]ErrorRecord{known}, 0)}
+	out := memoryOutput{}
+
+	err := Process(context.Background(), records, out, []Sink{MarkdownSink{Top: 2, BaseURL: "https://example.com/blob/main/"}}, opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `## report
+
+| | Количество |
+|-|-:|
+| Новые ошибки | 3 |
+| Известные ошибки | 1 |
+| Пропущены фильтрами | 0 |
+
+### Новые ошибки по модулям
+
+**ОбщийМодуль.В.Модуль** (2)
+
+- строка 5: Ошибка; Синтаксис: Вторая \*важная\*
+- строка 9: Ошибка; Синтаксис: Третья
+
+И еще ошибок: 1.
+`, out["report.md"].String())
+
+	out = memoryOutput{}
+	err = Process(context.Background(), records, out, []Sink{MarkdownSink{BaseURL: "https://example.com/blob/main/"}}, opts)
+
+	assert.NoError(t, err)
+	summary := out["report.md"].String()
+	assert.Contains(t, summary, "- [`cf/src/CommonModules/Б/Module.bsl:3`](https://example.com/blob/main/cf/src/CommonModules/%D0%91/Module.bsl#L3): Ошибка; Синтаксис: Первая\n")
+
+	truncated := truncateMarkdown(summary, 400)
+	assert.LessOrEqual(t, len(truncated), 400)
+	assert.True(t, strings.HasSuffix(truncated, "\n_Сводка сокращена._\n"), truncated)
+}
+
+func TestAnnotationSinks(t *testing.T) {
+	records := []ErrorRecord{
+		NewErrorRecord([]string{"", "Критическая", "Синтаксис", "cf", "std:1", "ОбщийМодуль.Б.Модуль", "строка 3, столбец 5", "Процедура, не определена: 100%"}),
+		NewErrorRecord([]string{"", "Тривиальная", "", "cf", "", "Справочник.Валюты", "", "Нет; описания"}),
+		NewErrorRecord([]string{"", "Моя", "", "cf", "", "Справочник.Валюты", "", "Своя"}),
+	}
+	records[0].Path = "cf/src/CommonModules/Б/Module.bsl"
+	opts := Options{Name: "report", Severities: map[string]string{"Моя": SeverityInfo}}
+
+	var github strings.Builder
+	err := Process(context.Background(), records, memoryOutput{}, []Sink{GitHubSink{PathPrefix: "src/", W: &github}}, opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `::error title=Критическая%2C Синтаксис,file=src/cf/src/CommonModules/Б/Module.bsl,line=3,col=5::ОбщийМодуль.Б.Модуль; строка 3, столбец 5; Процедура, не определена: 100%25
+::notice title=Тривиальная::Справочник.Валюты; ; Нет; описания
+::notice title=Моя::Справочник.Валюты; ; Своя
+`, github.String())
+
+	var azure strings.Builder
+	err = Process(context.Background(), records, memoryOutput{}, []Sink{AzureSink{W: &azure}}, opts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `##vso[task.logissue type=error;sourcepath=cf/src/CommonModules/Б/Module.bsl;linenumber=3;columnnumber=5;code=std:1;]Критическая, Синтаксис: ОбщийМодуль.Б.Модуль; строка 3, столбец 5; Процедура, не определена: 100%AZP25
+##vso[task.logissue type=warning;]Тривиальная: Справочник.Валюты; ; Нет; описания
+##vso[task.logissue type=warning;]Моя: Справочник.Валюты; ; Своя
+`, azure.String())
+}
+
+func TestTeamCitySink(t *testing.T) {
+	records := []ErrorRecord{
+		NewErrorRecord([]string{"", "Критическая", "Синтаксис", "cf", "", "ОбщийМодуль.Б.Модуль", "строка 3", "Процедура 'А' [не] определена"}),
+		NewErrorRecord([]string{"", "Критическая", "Синтаксис", "cf", "", "ОбщийМодуль.Б.Модуль", "строка 5", "Вторая|"}),
+	}
+	records[0].Path = "CommonModules/Б/Module.bsl"
+	var messages strings.Builder
+
+	err := Process(context.Background(), records, memoryOutput{}, []Sink{TeamCitySink{PathPrefix: "src/", W: &messages}}, Options{Name: "report"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `##teamcity[testSuiteStarted name='report_Критическая_Синтаксис']
+##teamcity[testStarted name='ОбщийМодуль.Б.Модуль:3']
+##teamcity[testFailed name='ОбщийМодуль.Б.Модуль:3' message='Критическая; Синтаксис; ' details='ОбщийМодуль.Б.Модуль; строка 3; Процедура |'А|' |[не|] определена']
+##teamcity[testFinished name='ОбщийМодуль.Б.Модуль:3']
+##teamcity[testStarted name='ОбщийМодуль.Б.Модуль:5']
+##teamcity[testFailed name='ОбщийМодуль.Б.Модуль:5' message='Критическая; Синтаксис; ' details='ОбщийМодуль.Б.Модуль; строка 5; Вторая||']
+##teamcity[testFinished name='ОбщийМодуль.Б.Модуль:5']
+##teamcity[testSuiteFinished name='report_Критическая_Синтаксис']
+##teamcity[inspectionType id='Синтаксис' name='Синтаксис' description='Синтаксис' category='Синтаксис']
+##teamcity[inspection typeId='Синтаксис' message='Процедура |'А|' |[не|] определена' file='src/CommonModules/Б/Module.bsl' line='3' SEVERITY='ERROR']
+##teamcity[inspection typeId='Синтаксис' message='Вторая||' file='ОбщийМодуль.Б.Модуль' line='5' SEVERITY='ERROR']
+`, messages.String())
+
+	messages.Reset()
+	err = Process(context.Background(), []ErrorRecord{records[1], records[1]}, memoryOutput{}, []Sink{TeamCitySink{W: &messages}}, Options{Name: "report"})
+	assert.NoError(t, err)
+	assert.Contains(t, messages.String(), "##teamcity[testStarted name='ОбщийМодуль.Б.Модуль:5']")
+	assert.Contains(t, messages.String(), "##teamcity[testStarted name='ОбщийМодуль.Б.Модуль:5 (2)']")
+}
+
+func TestAllureSink(t *testing.T) {
+	records := []ErrorRecord{
+		NewErrorRecord([]string{"2024-07-17T15:04:48+0300", "Критическая", "Синтаксис", "cf", "", "Справочник.Валюты.Форма.Форма.Форма.Модуль", "строка 3", "Одинаковая"}),
+		NewErrorRecord([]string{"2024-07-17T15:04:48+0300", "Критическая", "Синтаксис", "cf", "", "Справочник.Валюты.Форма.Форма.Форма.Модуль", "строка 9", "Одинаковая"}),
+	}
+	out := memoryOutput{}
+
+	err := Process(context.Background(), records, out, []Sink{AllureSink{}}, Options{Name: "report"})
+
+	assert.NoError(t, err)
+	assert.Len(t, out, 2)
+	var results []allureResult
+	for name, file := range out {
+		assert.True(t, strings.HasPrefix(name, "allure-results/") && strings.HasSuffix(name, "-result.json"), name)
+		var result allureResult
+		assert.NoError(t, json.Unmarshal([]byte(file.String()), &result))
+		assert.Equal(t, name, "allure-results/"+result.UUID+"-result.json")
+		results = append(results, result)
+	}
+
+	result := results[0]
+	assert.Equal(t, "failed", result.Status)
+	assert.Equal(t, records[0].Fingerprint(), result.TestCaseID)
+	assert.Equal(t, int64(1721217888000), result.Start)
+	assert.Equal(t, []allureLabel{
+		{Name: "parentSuite", Value: "report"},
+		{Name: "suite", Value: "Синтаксис"},
+		{Name: "feature", Value: "Справочник.Валюты"},
+		{Name: "story", Value: "Справочник.Валюты.Форма.Форма.Форма.Модуль"},
+		{Name: "severity", Value: "critical"},
+		{Name: "tag", Value: "cf"},
+	}, result.Labels)
+	assert.NotEqual(t, results[0].HistoryID, results[1].HistoryID)
+	assert.Equal(t, results[0].TestCaseID, results[1].TestCaseID)
+}
+
+func TestCheckstyleSink(t *testing.T) {
+	records := []ErrorRecord{
+		NewErrorRecord([]string{"", "Критическая", "Синтаксис", "cf", "std:1", "ОбщийМодуль.Б.Модуль", "строка 9", "Вторая"}),
+		NewErrorRecord([]string{"", "Тривиальная", "Стандарты", "cf", "", "Справочник.Валюты", "", "Нет описания"}),
+		NewErrorRecord([]string{"", "Значительная", "Синтаксис", "cf", "", "ОбщийМодуль.Б.Модуль", "строка 3, столбец 2", "Первая \x01"}),
+	}
+	records[0].Path = "CommonModules/Б/Module.bsl"
+	records[2].Path = "CommonModules/Б/Module.bsl"
+	out := memoryOutput{}
+
+	err := Process(context.Background(), records, out, []Sink{CheckstyleSink{PathPrefix: "src/"}}, Options{Name: "report"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, xml.Header+`<checkstyle version="8.0">
+    <file name="src/CommonModules/Б/Module.bsl">
+        <error line="3" column="2" severity="warning" message="Первая " source="Синтаксис"></error>
+        <error line="9" severity="error" message="Вторая" source="std:1"></error>
+    </file>
+    <file name="Справочник.Валюты">
+        <error severity="info" message="Нет описания" source="Стандарты"></error>
+    </file>
+</checkstyle>`, out["report.checkstyle.xml"].String())
+}
+
+func TestJSONSinks(t *testing.T) {
+	records := []ErrorRecord{
+		NewErrorRecord([]string{"", "Критическая", "Синтаксис", "cf", "std:1", "ОбщийМодуль.Б.Модуль", "строка 9", "Новая"}),
+		NewErrorRecord([]string{"", "Критическая", "Синтаксис", "cf", "std:1", "ОбщийМодуль.Б.Модуль", "строка 3", "Известная"}),
+		NewErrorRecord([]string{"", "Тривиальная", "Стандарты", "cf", "", "Справочник.Валюты", "", "Нет описания"}),
+	}
+	records[0].Path = "CommonModules/Б/Module.bsl"
+	out := memoryOutput{}
+	opts := Options{
+		Name:           "report",
+		SkipCategories: []string{"Стандарты"},
+		Baseline:       NewBaseline(records[1:2], 0),
+	}
+
+	err := Process(context.Background(), records, out, []Sink{JSONSink{}, JSONLinesSink{}}, opts)
+
+	assert.NoError(t, err)
+	var report struct {
+		Name    string       `json:"name"`
+		Records []JSONRecord `json:"records"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(out["report.json"].String()), &report))
+	assert.Equal(t, "report", report.Name)
+	if assert.Len(t, report.Records, 3) {
+		assert.Equal(t, JSONRecord{
+			Source:      "report",
+			Priority:    "Критическая",
+			Severity:    SeverityError,
+			CheckType:   "Синтаксис",
+			Project:     "cf",
+			Standard:    "std:1",
+			ErrorModule: "ОбщийМодуль.Б.Модуль",
+			ErrorLine:   "строка 9",
+			ErrorText:   "Новая",
+			Line:        9,
+			Path:        "CommonModules/Б/Module.bsl",
+			Fingerprint: records[0].Fingerprint(),
+			Status:      StatusReported,
+		}, report.Records[0])
+		assert.Equal(t, StatusBaselined, report.Records[1].Status)
+		assert.Equal(t, "baseline", report.Records[1].Reason)
+		assert.Equal(t, StatusSuppressed, report.Records[2].Status)
+		assert.Equal(t, "skip_categories", report.Records[2].Reason)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out["report.jsonl"].String(), "\n"), "\n")
+	if assert.Len(t, lines, 3) {
+		var record JSONRecord
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+		assert.Equal(t, report.Records[1], record)
+	}
+}
@@ -0,0 +1,118 @@
+package converter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Changes are the lines of the new files in the hunks of a unified diff.
+type Changes struct {
+	files map[string][]lineRange
+}
+
+type lineRange struct {
+	first, last int
+}
+
+var hunkPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseDiff reads the hunks of a unified diff, e.g. of git diff.
+// Deleted files and hunks which only delete lines are ignored.
+func ParseDiff(r io.Reader) (*Changes, error) {
+	changes := &Changes{files: make(map[string][]lineRange)}
+	var file string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if name, found := strings.CutPrefix(text, "+++ "); found {
+			file = diffPath(name)
+			continue
+		}
+
+		match := hunkPattern.FindStringSubmatch(text)
+		if match == nil || file == "" {
+			continue
+		}
+		first, _ := strconv.Atoi(match[1])
+		count := 1
+		if match[2] != "" {
+			count, _ = strconv.Atoi(match[2])
+		}
+		if count > 0 {
+			changes.files[file] = append(changes.files[file], lineRange{first, first + count - 1})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading diff: %w", err)
+	}
+	return changes, nil
+}
+
+// diffPath returns the path of the "+++" line without the "b/" prefix,
+// git quotes the paths with special and non-ASCII characters.
+func diffPath(name string) string {
+	name, _, _ = strings.Cut(name, "\t")
+	if strings.HasPrefix(name, `"`) {
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+	}
+	if name == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(name, "b/")
+}
+
+// GitDiff returns the changes of the working copy dir since the ref,
+// including the changes which are not committed.
+func GitDiff(dir string, ref string) (*Changes, error) {
+	output, err := gitOutput(dir, "-c", "core.quotePath=false", "diff", "--unified=0", "--no-color", "--no-ext-diff", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiff(strings.NewReader(string(output)))
+}
+
+// ReadChanges reads the diff file, or runs git diff in the dir when
+// the file is empty, see GitDiff.
+func ReadChanges(file string, dir string, ref string) (*Changes, error) {
+	if file == "" && ref == "" {
+		return nil, fmt.Errorf("either the diff file or the ref is required")
+	}
+	if file == "" {
+		return GitDiff(dir, ref)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseDiff(f)
+}
+
+// Contains reports whether the line of the file is changed. The diff paths
+// are relative to the repository root, the path matches the diff path
+// which it ends.
+func (c *Changes) Contains(path string, line int) bool {
+	if path == "" || line <= 0 {
+		return false
+	}
+	for file, ranges := range c.files {
+		if file != path && !strings.HasSuffix(file, "/"+path) {
+			continue
+		}
+		for _, r := range ranges {
+			if line >= r.first && line <= r.last {
+				return true
+			}
+		}
+	}
+	return false
+}
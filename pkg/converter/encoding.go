@@ -0,0 +1,104 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Encodings of the validation results, EncodingAuto detects the encoding
+// by the byte order mark and falls back to windows-1251 for invalid UTF-8.
+const (
+	EncodingAuto        = "auto"
+	EncodingUTF8        = "utf-8"
+	EncodingUTF16LE     = "utf-16le"
+	EncodingUTF16BE     = "utf-16be"
+	EncodingWindows1251 = "windows-1251"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DecodeText transcodes the data to UTF-8 without the byte order mark.
+func DecodeText(data []byte, name string) (string, error) {
+	if name == "" || strings.EqualFold(name, EncodingAuto) {
+		name = DetectEncoding(data)
+	}
+
+	var decoder *encoding.Decoder
+	switch strings.ToLower(name) {
+	case EncodingUTF8, "utf8":
+		return string(bytes.TrimPrefix(data, bomUTF8)), nil
+	case EncodingUTF16LE:
+		decoder = unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder()
+		if !bytes.HasPrefix(data, bomUTF16LE) {
+			decoder = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
+		}
+	case EncodingUTF16BE:
+		decoder = unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder()
+		if !bytes.HasPrefix(data, bomUTF16BE) {
+			decoder = unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder()
+		}
+	case EncodingWindows1251, "cp1251":
+		decoder = charmap.Windows1251.NewDecoder()
+	default:
+		return "", fmt.Errorf("unknown encoding %q", name)
+	}
+
+	decoded, err := decoder.Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("decoding %s: %w", name, err)
+	}
+	return string(decoded), nil
+}
+
+// DetectEncoding guesses the encoding of EDT output: the byte order mark
+// wins, UTF-16 without it is recognized by zero bytes in ASCII text,
+// and data which is not valid UTF-8 is taken as windows-1251.
+func DetectEncoding(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return EncodingUTF8
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return EncodingUTF16BE
+	}
+
+	if len(data) >= 2 && len(data)%2 == 0 {
+		if data[0] != 0 && data[1] == 0 {
+			return EncodingUTF16LE
+		}
+		if data[0] == 0 && data[1] != 0 {
+			return EncodingUTF16BE
+		}
+	}
+
+	if utf8.Valid(data) {
+		return EncodingUTF8
+	}
+	return EncodingWindows1251
+}
+
+// decodeReader reads r to the end and transcodes it, see DecodeText.
+func decodeReader(r io.Reader, name string) (io.Reader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := DecodeText(data, name)
+	if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(text), nil
+}
@@ -0,0 +1,268 @@
+package converter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Filter excludes error records from the report.
+type Filter interface {
+	// Name is used in the log to explain why a record was skipped.
+	Name() string
+	Skip(record ErrorRecord) bool
+}
+
+// FilterFactory creates a filter from its configuration options.
+type FilterFactory func(options map[string]any) (Filter, error)
+
+var filters = map[string]FilterFactory{}
+
+// RegisterFilter makes a filter available by name.
+// It is meant to be called from init functions and panics on duplicate names.
+func RegisterFilter(name string, factory FilterFactory) {
+	if _, found := filters[name]; found {
+		panic("converter: filter registered twice: " + name)
+	}
+	filters[name] = factory
+}
+
+// NewFilter creates a registered filter.
+func NewFilter(name string, options map[string]any) (Filter, error) {
+	factory, found := filters[name]
+	if !found {
+		return nil, fmt.Errorf("unknown filter %q, available: %v", name, Filters())
+	}
+	return factory(options)
+}
+
+// Filters returns the names of the registered filters.
+func Filters() []string {
+	return registryNames(filters)
+}
+
+type filterFunc struct {
+	name string
+	skip func(record ErrorRecord) bool
+}
+
+func (f filterFunc) Name() string {
+	return f.name
+}
+
+func (f filterFunc) Skip(record ErrorRecord) bool {
+	return f.skip(record)
+}
+
+// SkipObjectsFilter skips records of the modules containing any of the values.
+func SkipObjectsFilter(values []string) Filter {
+	return filterFunc{"skip_objects", func(record ErrorRecord) bool {
+		return recordInSkipObject(record, values)
+	}}
+}
+
+// SkipCategoriesFilter skips records of the check types.
+func SkipCategoriesFilter(values []string) Filter {
+	return filterFunc{"skip_categories", func(record ErrorRecord) bool {
+		return recordInSkipCategory(record, values)
+	}}
+}
+
+// SkipSignificanceCategoriesFilter skips records by "Significance_Category" values.
+func SkipSignificanceCategoriesFilter(values []string) Filter {
+	return filterFunc{"skip_significance_categories", func(record ErrorRecord) bool {
+		return recordInSkipSignificanteCategories(record, values)
+	}}
+}
+
+// SkipErrorTextFilter skips records with the error texts.
+func SkipErrorTextFilter(values []string) Filter {
+	return filterFunc{"skip_error_text", func(record ErrorRecord) bool {
+		return recordInSkipErrorText(record, values)
+	}}
+}
+
+// BaselineFilterName names the filters of the known errors, the sinks
+// report the records skipped by them as known.
+const BaselineFilterName = "baseline"
+
+// BaselineFilter skips records which are present in the baseline.
+func BaselineFilter(baseline *Baseline) Filter {
+	return filterFunc{BaselineFilterName, baseline.Contains}
+}
+
+// SkipRegexpFilter skips records with the field matching any of the patterns.
+func SkipRegexpFilter(field string, patterns []string) (Filter, error) {
+	if _, err := RecordField(ErrorRecord{}, field); err != nil {
+		return nil, err
+	}
+
+	var expressions []*regexp.Regexp
+	for _, pattern := range patterns {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, expression)
+	}
+
+	return filterFunc{"skip_regexp", func(record ErrorRecord) bool {
+		value, _ := RecordField(record, field)
+		for _, expression := range expressions {
+			if expression.MatchString(value) {
+				return true
+			}
+		}
+		return false
+	}}, nil
+}
+
+// AuthorsFilter skips records which are not changed last by any of the
+// authors, given by name or email, see Options.Blame.
+func AuthorsFilter(authors []string) Filter {
+	return filterFunc{"authors", func(record ErrorRecord) bool {
+		for _, author := range authors {
+			if author == record.Author || author == record.AuthorMail {
+				return false
+			}
+		}
+		return true
+	}}
+}
+
+// OwnersFilter skips records which are not owned by any of the owners,
+// see Options.Owners.
+func OwnersFilter(owners []string) Filter {
+	return filterFunc{"owners", func(record ErrorRecord) bool {
+		for _, owner := range strings.Fields(record.Owner) {
+			for _, value := range owners {
+				if owner == value {
+					return false
+				}
+			}
+		}
+		return true
+	}}
+}
+
+// BlameSinceFilter skips records on the lines which are not changed by
+// the commits, see CommitsSince. Uncommitted lines are kept.
+func BlameSinceFilter(commits map[string]struct{}) Filter {
+	return filterFunc{"blame_since", func(record ErrorRecord) bool {
+		if record.Commit == uncommitted {
+			return false
+		}
+		_, found := commits[record.Commit]
+		return !found
+	}}
+}
+
+// ChangesFilter skips records outside of the changed lines, see ParseDiff.
+func ChangesFilter(changes *Changes) Filter {
+	return filterFunc{"diff", func(record ErrorRecord) bool {
+		return !changes.Contains(record.Path, record.Line)
+	}}
+}
+
+type valuesOptions struct {
+	Values []string `json:"values"`
+}
+
+func registerValuesFilter(name string, newFilter func(values []string) Filter) {
+	RegisterFilter(name, func(options map[string]any) (Filter, error) {
+		var opts valuesOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return newFilter(opts.Values), nil
+	})
+}
+
+type regexpOptions struct {
+	Field  string   `json:"field"`
+	Values []string `json:"values"`
+}
+
+type baselineOptions struct {
+	File          string `json:"file"`
+	LineTolerance int    `json:"line_tolerance"`
+}
+
+type blameSinceOptions struct {
+	Ref string `json:"ref"`
+	Dir string `json:"dir"`
+}
+
+type diffOptions struct {
+	File string `json:"file"`
+	Ref  string `json:"ref"`
+	Dir  string `json:"dir"`
+}
+
+func init() {
+	registerValuesFilter("skip_objects", SkipObjectsFilter)
+	registerValuesFilter("skip_categories", SkipCategoriesFilter)
+	registerValuesFilter("skip_significance_categories", SkipSignificanceCategoriesFilter)
+	registerValuesFilter("skip_error_text", SkipErrorTextFilter)
+	registerValuesFilter("authors", AuthorsFilter)
+	registerValuesFilter("owners", OwnersFilter)
+
+	RegisterFilter("skip_regexp", func(options map[string]any) (Filter, error) {
+		opts := regexpOptions{Field: "error_text"}
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return SkipRegexpFilter(opts.Field, opts.Values)
+	})
+
+	RegisterFilter(BaselineFilterName, func(options map[string]any) (Filter, error) {
+		var opts baselineOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		if opts.File == "" {
+			return nil, fmt.Errorf("baseline filter requires the file option")
+		}
+
+		file, err := os.Open(opts.File)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		records, err := ReadTSV(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading baseline %s: %w", opts.File, err)
+		}
+		return BaselineFilter(NewBaseline(records, opts.LineTolerance)), nil
+	})
+
+	RegisterFilter("blame_since", func(options map[string]any) (Filter, error) {
+		var opts blameSinceOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		if opts.Ref == "" {
+			return nil, fmt.Errorf("blame_since filter requires the ref option")
+		}
+
+		commits, err := CommitsSince(opts.Dir, opts.Ref)
+		if err != nil {
+			return nil, err
+		}
+		return BlameSinceFilter(commits), nil
+	})
+
+	RegisterFilter("diff", func(options map[string]any) (Filter, error) {
+		var opts diffOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		changes, err := ReadChanges(opts.File, opts.Dir, opts.Ref)
+		if err != nil {
+			return nil, err
+		}
+		return ChangesFilter(changes), nil
+	})
+}
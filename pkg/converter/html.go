@@ -0,0 +1,100 @@
+package converter
+
+import (
+	"context"
+	_ "embed"
+	"html/template"
+	"io"
+	"log/slog"
+)
+
+// DefaultTop is the number of the objects in the summaries.
+const DefaultTop = 10
+
+//go:embed html.tmpl
+var htmlTemplateText string
+
+var htmlTemplate = template.Must(template.New("html").Funcs(template.FuncMap{
+	"counts": func(title string, counts []Count) map[string]any {
+		return map[string]any{"Title": title, "Counts": counts}
+	},
+}).Parse(htmlTemplateText))
+
+// HTMLSink writes the report into a self-contained <name>.html with
+// the summaries and the sortable list of the records.
+type HTMLSink struct {
+	// Top limits the objects in the summary, zero means DefaultTop.
+	Top int
+
+	// Logger receives debug messages, nil means slog.Default.
+	Logger *slog.Logger
+}
+
+// htmlReport is the data of the html template.
+type htmlReport struct {
+	Report
+	Total          int
+	SkippedTotal   int
+	Baselined      int
+	BySignificance []Count
+	ByCategory     []Count
+	ByProject      []Count
+	TopObjects     []Count
+	Suppressed     []Count
+	HasOwners      bool
+	HasAuthors     bool
+}
+
+func (s HTMLSink) Write(ctx context.Context, out Output, report Report) error {
+	top := s.Top
+	if top <= 0 {
+		top = DefaultTop
+	}
+
+	data := htmlReport{
+		Report:       report,
+		Total:        len(report.Records),
+		SkippedTotal: len(report.Skipped),
+		BySignificance: countBy(report.Records, func(record ErrorRecord) string {
+			return record.Priority
+		}),
+		ByCategory: countBy(report.Records, func(record ErrorRecord) string {
+			return record.CheckType
+		}),
+		ByProject: countBy(report.Records, func(record ErrorRecord) string {
+			return record.Project
+		}),
+		TopObjects: first(countBy(report.Records, func(record ErrorRecord) string {
+			return topObject(record.ErrorModule)
+		}), top),
+		Suppressed: countSkipped(report.Skipped),
+	}
+	for _, record := range report.Skipped {
+		if record.Filter == BaselineFilterName {
+			data.Baselined++
+		}
+	}
+	for _, record := range report.Records {
+		data.HasOwners = data.HasOwners || record.Owner != ""
+		data.HasAuthors = data.HasAuthors || record.Author != ""
+	}
+
+	loggerOrDefault(s.Logger).Debug("writing html report", "name", report.Name, "records", data.Total)
+	return writeFile(out, report.Name+".html", func(w io.Writer) error {
+		return htmlTemplate.Execute(w, data)
+	})
+}
+
+type htmlOptions struct {
+	Top int `json:"top"`
+}
+
+func init() {
+	RegisterSink("html", func(options map[string]any) (Sink, error) {
+		var opts htmlOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return HTMLSink{Top: opts.Top}, nil
+	})
+}
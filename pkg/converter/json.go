@@ -0,0 +1,146 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+)
+
+// Statuses of the exported records.
+const (
+	StatusReported   = "reported"
+	StatusBaselined  = "baselined"
+	StatusSuppressed = "suppressed"
+)
+
+// JSONRecord is a normalized record of the JSON export, the fields are
+// named like in RecordField.
+type JSONRecord struct {
+	Source      string `json:"source"`
+	Date        string `json:"date"`
+	Priority    string `json:"priority"`
+	Severity    string `json:"severity"`
+	CheckType   string `json:"check_type"`
+	Project     string `json:"project"`
+	Standard    string `json:"standard"`
+	ErrorModule string `json:"error_module"`
+	ErrorLine   string `json:"error_line"`
+	ErrorText   string `json:"error_text"`
+	Line        int    `json:"line,omitempty"`
+	Column      int    `json:"column,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	Commit      string `json:"commit,omitempty"`
+	Author      string `json:"author,omitempty"`
+	AuthorMail  string `json:"author_mail,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	// Status is StatusReported, StatusBaselined or StatusSuppressed.
+	Status string `json:"status"`
+	// Reason is the filter which skipped the record.
+	Reason string `json:"reason,omitempty"`
+}
+
+// NewJSONRecords returns the reported records followed by the skipped ones.
+func NewJSONRecords(report Report) []JSONRecord {
+	records := make([]JSONRecord, 0, len(report.Records)+len(report.Skipped))
+	for _, record := range report.Records {
+		records = append(records, newJSONRecord(record, StatusReported, ""))
+	}
+	for _, skipped := range report.Skipped {
+		status := StatusSuppressed
+		if skipped.Filter == BaselineFilterName {
+			status = StatusBaselined
+		}
+		records = append(records, newJSONRecord(skipped.Record, status, skipped.Filter))
+	}
+	return records
+}
+
+func newJSONRecord(record ErrorRecord, status string, reason string) JSONRecord {
+	return JSONRecord{
+		Source:      record.Source,
+		Date:        record.Date,
+		Priority:    record.Priority,
+		Severity:    record.Severity,
+		CheckType:   record.CheckType,
+		Project:     record.Project,
+		Standard:    record.Standard,
+		ErrorModule: record.ErrorModule,
+		ErrorLine:   record.ErrorLine,
+		ErrorText:   record.ErrorText,
+		Line:        record.Line,
+		Column:      record.Column,
+		Path:        record.Path,
+		Owner:       record.Owner,
+		Commit:      record.Commit,
+		Author:      record.Author,
+		AuthorMail:  record.AuthorMail,
+		Fingerprint: record.Fingerprint(),
+		Status:      status,
+		Reason:      reason,
+	}
+}
+
+// JSONSink writes the report with the skipped records into <name>.json.
+type JSONSink struct {
+	// Logger receives debug messages, nil means slog.Default.
+	Logger *slog.Logger
+}
+
+type jsonReport struct {
+	Name      string       `json:"name"`
+	Timestamp string       `json:"timestamp"`
+	Records   []JSONRecord `json:"records"`
+}
+
+func (s JSONSink) Write(ctx context.Context, out Output, report Report) error {
+	data := jsonReport{Name: report.Name, Timestamp: report.Timestamp, Records: NewJSONRecords(report)}
+	loggerOrDefault(s.Logger).Debug("writing json report", "name", report.Name, "records", len(data.Records))
+
+	return writeFile(out, report.Name+".json", func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	})
+}
+
+// JSONLinesSink writes a record per line, including the skipped ones,
+// into <name>.jsonl.
+type JSONLinesSink struct {
+	// Logger receives debug messages, nil means slog.Default.
+	Logger *slog.Logger
+}
+
+func (s JSONLinesSink) Write(ctx context.Context, out Output, report Report) error {
+	records := NewJSONRecords(report)
+	loggerOrDefault(s.Logger).Debug("writing json lines report", "name", report.Name, "records", len(records))
+
+	return writeFile(out, report.Name+".jsonl", func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func init() {
+	RegisterSink("json", func(options map[string]any) (Sink, error) {
+		if err := decodeOptions(options, &struct{}{}); err != nil {
+			return nil, err
+		}
+		return JSONSink{}, nil
+	})
+	RegisterSink("jsonl", func(options map[string]any) (Sink, error) {
+		if err := decodeOptions(options, &struct{}{}); err != nil {
+			return nil, err
+		}
+		return JSONLinesSink{}, nil
+	})
+}
@@ -0,0 +1,303 @@
+package converter
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strconv"
+)
+
+type TestSuites struct {
+	XMLName   xml.Name    `xml:"testsuites"`
+	Time      string      `xml:"time,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Failures  int         `xml:"failures,attr"`
+	TestSuite []TestSuite `xml:"testsuite"`
+}
+
+type TestSuite struct {
+	XMLName    xml.Name   `xml:"testsuite"`
+	Name       string     `xml:"name,attr"`
+	Timestamp  string     `xml:"timestamp,attr"`
+	Time       string     `xml:"time,attr"`
+	Tests      int        `xml:"tests,attr"`
+	Errors     int        `xml:"errors,attr"`
+	Failures   int        `xml:"failures,attr"`
+	Skipped    int        `xml:"skipped,attr"`
+	Properties []Property `xml:"properties>property"`
+	TestCases  []TestCase `xml:"testcase"`
+}
+
+type Property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type TestCase struct {
+	XMLName    xml.Name    `xml:"testcase"`
+	ClassName  string      `xml:"classname,attr"`
+	Name       string      `xml:"name,attr"`
+	Time       string      `xml:"time,attr"`
+	File       string      `xml:"file,attr,omitempty"`
+	Line       int         `xml:"line,attr,omitempty"`
+	Properties *Properties `xml:"properties"`
+	Failures   []Failure   `xml:"failure"`
+}
+
+// Properties of a test case are written only when there are any.
+type Properties struct {
+	Property []Property `xml:"property"`
+}
+
+type Failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+
+	// record is the source of the failure.
+	record ErrorRecord
+}
+
+func getTestSuiteByName(testSuites TestSuites, testSuiteTimestamp string, fileName string, recordName string, logger slog.Logger) (TestSuite, int) {
+	if len(testSuites.TestSuite) == 0 {
+		ts := TestSuite{
+			Name:       fileName + "_" + recordName,
+			Timestamp:  testSuiteTimestamp,
+			Time:       "0",
+			Tests:      0,
+			Errors:     0,
+			Failures:   0,
+			Skipped:    0,
+			Properties: []Property{},
+			TestCases:  []TestCase{},
+		}
+		logger.Debug("new test suite", "name", fileName+"_"+recordName)
+		return ts, -1
+	} else {
+		for index, ts := range testSuites.TestSuite {
+			if ts.Name == fileName+"_"+recordName {
+				logger.Debug("found test suite", "name", fileName+"_"+recordName)
+				return ts, index
+			}
+		}
+		ts := TestSuite{
+			Name:       fileName + "_" + recordName,
+			Timestamp:  testSuiteTimestamp,
+			Time:       "0",
+			Tests:      0,
+			Errors:     0,
+			Failures:   0,
+			Skipped:    0,
+			Properties: []Property{},
+			TestCases:  []TestCase{},
+		}
+		logger.Debug("created test suite", "name", fileName+"_"+recordName)
+		return ts, -1
+	}
+}
+
+func getTestCaseByName(testSuite TestSuite, testCaseName string, logger slog.Logger) (TestCase, int) {
+	for index, tc := range testSuite.TestCases {
+		if tc.Name == testCaseName {
+			logger.Debug("found test case", "name", testCaseName)
+			return tc, index
+		}
+	}
+	tc := TestCase{
+		ClassName: "",
+		Name:      testCaseName,
+		Time:      fmt.Sprintf("%f", 0.01),
+		Failures:  []Failure{},
+	}
+	logger.Debug("created test case", "name", testCaseName)
+	return tc, -1
+}
+
+// recordProperties returns the owner of the record and the author and
+// the commit of the record line, nil when there are none of them.
+func recordProperties(record ErrorRecord) *Properties {
+	var properties []Property
+	if record.Owner != "" {
+		properties = append(properties, Property{Name: "owner", Value: record.Owner})
+	}
+	if record.Commit != "" {
+		properties = append(properties,
+			Property{Name: "author", Value: record.Author},
+			Property{Name: "author_mail", Value: record.AuthorMail},
+			Property{Name: "commit", Value: record.Commit})
+	}
+	if properties == nil {
+		return nil
+	}
+	return &Properties{Property: properties}
+}
+
+// WriteXML writes the xml header and the indented test suites.
+func WriteXML(w io.Writer, testSuites TestSuites) error {
+	xmlData, err := xml.MarshalIndent(testSuites, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshaling xml: %w", err)
+	}
+
+	_, err = w.Write([]byte(xml.Header))
+	if err != nil {
+		return fmt.Errorf("writing header: %w", err)
+	}
+
+	_, err = w.Write(xmlData)
+	if err != nil {
+		return fmt.Errorf("writing xml data: %w", err)
+	}
+	return nil
+}
+
+// NewTestSuites groups the report records into test suites by source,
+// significance and category, and into test cases by module.
+func NewTestSuites(report Report, logger *slog.Logger) TestSuites {
+	testSuites := TestSuites{
+		Time:      "0",
+		Tests:     0,
+		Errors:    0,
+		Failures:  0,
+		TestSuite: []TestSuite{},
+	}
+
+	for _, record := range report.Records {
+		source := record.Source
+		if source == "" {
+			source = report.Name
+		}
+		testSuite, indexTestSuite := getTestSuiteByName(testSuites, report.Timestamp, source, record.Priority+"_"+record.CheckType, *logger)
+		testCase, indexTestCase := getTestCaseByName(testSuite, record.ErrorModule, *logger)
+
+		failure := Failure{}
+		failure.Type = record.CheckType
+		failure.Message = record.Priority + "; " + record.CheckType + "; " + record.Standard
+		failure.Text = record.ErrorModule + "; " + record.ErrorLine + "; " + record.ErrorText
+		failure.record = record
+		logger.Debug("added failure", "type", failure.Type, "message", failure.Message, "text", failure.Text)
+		testCase.Failures = append(testCase.Failures, failure)
+
+		if indexTestCase == -1 {
+			testSuite.TestCases = append(testSuite.TestCases, testCase)
+		} else {
+			testSuite.TestCases[indexTestCase] = testCase
+		}
+		testSuite.Tests++
+		testSuite.Failures++
+
+		if indexTestSuite == -1 {
+			testSuites.TestSuite = append(testSuites.TestSuite, testSuite)
+		} else {
+			testSuites.TestSuite[indexTestSuite] = testSuite
+		}
+		testSuites.Tests++
+		testSuites.Failures++
+	}
+
+	for index_ts, ts := range testSuites.TestSuite {
+		var newTestCases []TestCase
+		for _, tc := range ts.TestCases {
+			sort.SliceStable(tc.Failures, func(i, j int) bool {
+				return tc.Failures[i].record.Line < tc.Failures[j].record.Line
+			})
+			if len(tc.Failures) > 1 {
+				for i, f := range tc.Failures {
+					newTestCase := TestCase{
+						ClassName:  tc.ClassName + "_unique_" + strconv.Itoa(i),
+						Name:       tc.Name,
+						Time:       tc.Time,
+						File:       f.record.Path,
+						Line:       f.record.Line,
+						Properties: recordProperties(f.record),
+						Failures:   []Failure{f},
+					}
+					newTestCases = append(newTestCases, newTestCase)
+					logger.Debug("added new test case", "name", newTestCase.Name, "class", newTestCase.ClassName)
+				}
+			} else {
+				tc.File = tc.Failures[0].record.Path
+				tc.Line = tc.Failures[0].record.Line
+				tc.Properties = recordProperties(tc.Failures[0].record)
+				newTestCases = append(newTestCases, tc)
+			}
+
+		}
+		testSuites.TestSuite[index_ts].TestCases = newTestCases
+	}
+
+	return testSuites
+}
+
+// JUnitSink writes the report into <name>.xml. Characters which are not
+// allowed in XML and control codes are replaced, the count is logged.
+// When the report has a source folder the failures show the source lines
+// around the reported line.
+type JUnitSink struct {
+	// Replacement of the invalid characters, empty removes them.
+	Replacement string
+	// MaxFailureLength limits the failure body in characters, zero means no limit.
+	MaxFailureLength int
+	// ContextLines is the number of the source lines shown before and after
+	// the reported line, negative disables the source lines.
+	ContextLines int
+
+	// Logger receives debug messages, nil means slog.Default.
+	Logger *slog.Logger
+}
+
+func (s JUnitSink) Write(ctx context.Context, out Output, report Report) error {
+	logger := loggerOrDefault(s.Logger)
+	testSuites := NewTestSuites(report, logger)
+	if report.SourceDir != "" && s.ContextLines >= 0 {
+		addSnippets(&testSuites, newSourceFiles(report.SourceDir), s.ContextLines)
+	}
+	if replacements := sanitizeTestSuites(&testSuites, s.Replacement, s.MaxFailureLength); replacements > 0 {
+		logger.Warn("replaced invalid xml characters", "file", report.Name+".xml", "replacements", replacements)
+	}
+
+	return writeFile(out, report.Name+".xml", func(w io.Writer) error {
+		return WriteXML(w, testSuites)
+	})
+}
+
+// addSnippets appends the source lines to the failures with a module path.
+func addSnippets(testSuites *TestSuites, files *sourceFiles, contextLines int) {
+	for i := range testSuites.TestSuite {
+		for j := range testSuites.TestSuite[i].TestCases {
+			failures := testSuites.TestSuite[i].TestCases[j].Failures
+			for k, f := range failures {
+				if f.record.Path == "" {
+					continue
+				}
+				if snippet := files.snippet(f.record.Path, f.record.Line, contextLines); snippet != "" {
+					failures[k].Text += "\n\n" + snippet
+				}
+			}
+		}
+	}
+}
+
+type junitOptions struct {
+	Replacement      string `json:"replacement"`
+	MaxFailureLength int    `json:"max_failure_length"`
+	ContextLines     *int   `json:"context_lines"`
+}
+
+func init() {
+	RegisterSink("junit", func(options map[string]any) (Sink, error) {
+		var opts junitOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		contextLines := DefaultContextLines
+		if opts.ContextLines != nil {
+			contextLines = *opts.ContextLines
+		}
+		return JUnitSink{Replacement: opts.Replacement, MaxFailureLength: opts.MaxFailureLength, ContextLines: contextLines}, nil
+	})
+}
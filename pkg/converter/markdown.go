@@ -0,0 +1,152 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// DefaultMaxMarkdownLength fits the comment limit of GitHub.
+const DefaultMaxMarkdownLength = 65000
+
+// MarkdownSink writes a compact summary of the report into <name>.md,
+// e.g. for a merge request comment.
+type MarkdownSink struct {
+	// Top limits the listed records, zero means DefaultTop.
+	Top int
+	// BaseURL links the module paths, e.g. https://example.com/repo/blob/main/,
+	// the path and the line anchor #L<line> are appended to it.
+	BaseURL string
+	// MaxLength limits the summary in bytes, zero means DefaultMaxMarkdownLength.
+	MaxLength int
+
+	// Logger receives debug messages, nil means slog.Default.
+	Logger *slog.Logger
+}
+
+func (s MarkdownSink) Write(ctx context.Context, out Output, report Report) error {
+	top := s.Top
+	if top <= 0 {
+		top = DefaultTop
+	}
+	maxLength := s.MaxLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxMarkdownLength
+	}
+
+	baselined := 0
+	for _, record := range report.Skipped {
+		if record.Filter == BaselineFilterName {
+			baselined++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", escapeMarkdown(report.Name))
+	b.WriteString("| | Количество |\n|-|-:|\n")
+	fmt.Fprintf(&b, "| Новые ошибки | %d |\n", len(report.Records))
+	fmt.Fprintf(&b, "| Известные ошибки | %d |\n", baselined)
+	fmt.Fprintf(&b, "| Пропущены фильтрами | %d |\n", len(report.Skipped)-baselined)
+
+	if len(report.Records) > 0 {
+		fmt.Fprintf(&b, "\n### Новые ошибки по модулям\n")
+		listed := 0
+		modules := countBy(report.Records, func(record ErrorRecord) string {
+			return record.ErrorModule
+		})
+		for _, module := range modules {
+			if listed >= top {
+				break
+			}
+			fmt.Fprintf(&b, "\n**%s** (%d)\n\n", escapeMarkdown(module.Name), module.Count)
+			for _, record := range report.Records {
+				if record.ErrorModule != module.Name || listed >= top {
+					continue
+				}
+				b.WriteString(s.markdownRecord(record))
+				listed++
+			}
+		}
+		if rest := len(report.Records) - listed; rest > 0 {
+			fmt.Fprintf(&b, "\nИ еще ошибок: %d.\n", rest)
+		}
+	}
+
+	summary := truncateMarkdown(b.String(), maxLength)
+	loggerOrDefault(s.Logger).Debug("writing markdown summary", "name", report.Name, "length", len(summary))
+	return writeFile(out, report.Name+".md", func(w io.Writer) error {
+		_, err := io.WriteString(w, summary)
+		return err
+	})
+}
+
+// markdownRecord is a list item with the location, the significance,
+// the category and the text of the record.
+func (s MarkdownSink) markdownRecord(record ErrorRecord) string {
+	location := record.ErrorLine
+	if record.Path != "" {
+		location = record.Path
+		if record.Line > 0 {
+			location += fmt.Sprintf(":%d", record.Line)
+		}
+		location = "`" + location + "`"
+		if s.BaseURL != "" {
+			segments := strings.Split(record.Path, "/")
+			for i, segment := range segments {
+				segments[i] = url.PathEscape(segment)
+			}
+			link := s.BaseURL + strings.Join(segments, "/")
+			if record.Line > 0 {
+				link += fmt.Sprintf("#L%d", record.Line)
+			}
+			location = "[" + location + "](" + link + ")"
+		}
+	} else {
+		location = escapeMarkdown(location)
+	}
+
+	return fmt.Sprintf("- %s: %s; %s: %s\n", location, escapeMarkdown(record.Priority), escapeMarkdown(record.CheckType), escapeMarkdown(record.ErrorText))
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", "&lt;", ">", "&gt;", "|", `\|`, "#", `\#`, "\n", " ", "\r", "",
+)
+
+// escapeMarkdown keeps the text on one line and prevents the formatting.
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// truncateMarkdown cuts the text at a line end so that it fits the limit
+// with the note about the truncation.
+func truncateMarkdown(text string, maxLength int) string {
+	if len(text) <= maxLength {
+		return text
+	}
+	const note = "\n_Сводка сокращена._\n"
+	cut := strings.LastIndex(text[:max(maxLength-len(note), 0)], "\n")
+	if cut < 0 {
+		cut = 0
+	}
+	return text[:cut] + note
+}
+
+type markdownOptions struct {
+	Top       int    `json:"top"`
+	BaseURL   string `json:"base_url"`
+	MaxLength int    `json:"max_length"`
+}
+
+func init() {
+	RegisterSink("markdown", func(options map[string]any) (Sink, error) {
+		var opts markdownOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return MarkdownSink{Top: opts.Top, BaseURL: opts.BaseURL, MaxLength: opts.MaxLength}, nil
+	})
+}
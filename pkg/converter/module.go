@@ -0,0 +1,154 @@
+package converter
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// metadataFolders maps the metadata types of the module names in Russian
+// and English to the folders of the EDT project.
+var metadataFolders = map[string]string{
+	"Конфигурация":         "Configuration",
+	"Configuration":        "Configuration",
+	"Справочник":           "Catalogs",
+	"Catalog":              "Catalogs",
+	"Документ":             "Documents",
+	"Document":             "Documents",
+	"ЖурналДокументов":     "DocumentJournals",
+	"DocumentJournal":      "DocumentJournals",
+	"Обработка":            "DataProcessors",
+	"DataProcessor":        "DataProcessors",
+	"Отчет":                "Reports",
+	"Report":               "Reports",
+	"ОбщийМодуль":          "CommonModules",
+	"CommonModule":         "CommonModules",
+	"ОбщаяФорма":           "CommonForms",
+	"CommonForm":           "CommonForms",
+	"ОбщаяКоманда":         "CommonCommands",
+	"CommonCommand":        "CommonCommands",
+	"Константа":            "Constants",
+	"Constant":             "Constants",
+	"Перечисление":         "Enums",
+	"Enum":                 "Enums",
+	"РегистрСведений":      "InformationRegisters",
+	"InformationRegister":  "InformationRegisters",
+	"РегистрНакопления":    "AccumulationRegisters",
+	"AccumulationRegister": "AccumulationRegisters",
+	"РегистрБухгалтерии":   "AccountingRegisters",
+	"AccountingRegister":   "AccountingRegisters",
+	"РегистрРасчета":       "CalculationRegisters",
+	"CalculationRegister":  "CalculationRegisters",
+	"ПланВидовХарактеристик":     "ChartsOfCharacteristicTypes",
+	"ChartOfCharacteristicTypes": "ChartsOfCharacteristicTypes",
+	"ПланСчетов":                 "ChartsOfAccounts",
+	"ChartOfAccounts":            "ChartsOfAccounts",
+	"ПланВидовРасчета":           "ChartsOfCalculationTypes",
+	"ChartOfCalculationTypes":    "ChartsOfCalculationTypes",
+	"ПланОбмена":                 "ExchangePlans",
+	"ExchangePlan":               "ExchangePlans",
+	"БизнесПроцесс":              "BusinessProcesses",
+	"BusinessProcess":            "BusinessProcesses",
+	"Задача":                     "Tasks",
+	"Task":                       "Tasks",
+	"Последовательность":         "Sequences",
+	"Sequence":                   "Sequences",
+	"ХранилищеНастроек":          "SettingsStorages",
+	"SettingsStorage":            "SettingsStorages",
+	"ВебСервис":                  "WebServices",
+	"WebService":                 "WebServices",
+	"HTTPСервис":                 "HTTPServices",
+	"HTTPService":                "HTTPServices",
+}
+
+// moduleFiles maps the module kinds to the file names.
+var moduleFiles = map[string]string{
+	"Модуль":                       "Module.bsl",
+	"МодульОбъекта":                "ObjectModule.bsl",
+	"МодульМенеджера":              "ManagerModule.bsl",
+	"МодульНабораЗаписей":          "RecordSetModule.bsl",
+	"МодульКоманды":                "CommandModule.bsl",
+	"МодульМенеджераЗначения":      "ValueManagerModule.bsl",
+	"МодульУправляемогоПриложения": "ManagedApplicationModule.bsl",
+	"МодульОбычногоПриложения":     "OrdinaryApplicationModule.bsl",
+	"МодульСеанса":                 "SessionModule.bsl",
+	"МодульВнешнегоСоединения":     "ExternalConnectionModule.bsl",
+}
+
+func init() {
+	for _, file := range moduleFiles {
+		moduleFiles[strings.TrimSuffix(file, ".bsl")] = file
+	}
+}
+
+// ModulePath returns the slash separated path of the module file inside
+// the src folder of the EDT project, e.g. "ОбщийМодуль.Б.Модуль" is
+// "CommonModules/Б/Module.bsl". It returns an empty string for the names
+// which are not modules.
+func ModulePath(module string) string {
+	parts := strings.Split(module, ".")
+	folder, found := metadataFolders[parts[0]]
+	if !found || len(parts) < 2 {
+		return ""
+	}
+
+	elements := []string{folder}
+	rest := parts[1:]
+	if folder == "Configuration" {
+		rest = rest[len(rest)-1:]
+	} else {
+		if len(rest) < 2 {
+			return ""
+		}
+		elements = append(elements, rest[0])
+		rest = rest[1:]
+	}
+
+	if len(rest) >= 3 {
+		switch rest[0] {
+		case "Форма", "Form":
+			elements = append(elements, "Forms", rest[1])
+			rest = rest[2:]
+		case "Команда", "Command":
+			elements = append(elements, "Commands", rest[1])
+			rest = rest[2:]
+		}
+	}
+	// The form module is named after the form object, e.g. "Форма.Модуль".
+	if len(rest) == 2 && (rest[0] == "Форма" || rest[0] == "Form") {
+		rest = rest[1:]
+	}
+
+	if len(rest) != 1 {
+		return ""
+	}
+	file, found := moduleFiles[rest[0]]
+	if !found {
+		return ""
+	}
+	return path.Join(append(elements, file)...)
+}
+
+// ResolveModule finds the file of the record module in the source folder,
+// which is the EDT project, its src folder or the workspace with
+// the projects named by the Project column. It returns the slash separated
+// path relative to the source folder, or an empty string when the file
+// does not exist.
+func ResolveModule(sourceDir string, record ErrorRecord) string {
+	modulePath := ModulePath(record.ErrorModule)
+	if modulePath == "" {
+		return ""
+	}
+
+	candidates := []string{path.Join("src", modulePath), modulePath}
+	if record.Project != "" {
+		candidates = append([]string{path.Join(record.Project, "src", modulePath)}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(filepath.Join(sourceDir, filepath.FromSlash(candidate))); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
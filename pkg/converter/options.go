@@ -0,0 +1,27 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// decodeOptions fills v from the plugin options declared in the config.
+// Unknown option names are reported as errors.
+func decodeOptions(options map[string]any, v any) error {
+	if len(options) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("encoding options: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("decoding options: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package converter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/azheval/conv_edt_tsv_junit/pkg/discovery"
+)
+
+// Owners assign the records to the teams by the rules of a CODEOWNERS
+// style file, the last matching rule wins.
+type Owners struct {
+	rules []ownerRule
+}
+
+type ownerRule struct {
+	pattern string
+	// metadata rules match the module names, the others match the paths.
+	metadata bool
+	owners   []string
+}
+
+// ParseOwners reads the rules, one per line: a pattern followed by
+// the owners. Blank lines and lines starting with "#" are ignored.
+//
+// Patterns starting with a metadata type, e.g. "Справочник.Валюты" or
+// "ОбщийМодуль.*", match the module names and everything inside them.
+// Other patterns match the module paths relative to Options.SourceDir
+// like in CODEOWNERS: "/" at the start or in the middle anchors
+// the pattern, "/" at the end matches a folder, "**" matches any folders.
+// The records without a path are matched by the module name with
+// the patterns without "/", so "*" matches every record.
+func ParseOwners(r io.Reader) (*Owners, error) {
+	owners := &Owners{}
+	scanner := bufio.NewScanner(r)
+	number := 0
+	for scanner.Scan() {
+		number++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		rule := ownerRule{pattern: fields[0], owners: fields[1:]}
+		rule.metadata = !strings.Contains(rule.pattern, "/") && metadataFolders[strings.Split(rule.pattern, ".")[0]] != ""
+		if err := rule.check(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		owners.rules = append(owners.rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+// ReadOwners reads the owners file, see ParseOwners.
+func ReadOwners(file string) (*Owners, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	owners, err := ParseOwners(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading owners %s: %w", file, err)
+	}
+	return owners, nil
+}
+
+// Of returns the owners of the record, nil when no rule matches or
+// the matching rule has no owners.
+func (o *Owners) Of(record ErrorRecord) []string {
+	for i := len(o.rules) - 1; i >= 0; i-- {
+		if o.rules[i].match(record) {
+			return o.rules[i].owners
+		}
+	}
+	return nil
+}
+
+func (r ownerRule) check() error {
+	for _, element := range strings.FieldsFunc(r.pattern, func(c rune) bool { return c == '/' || c == '.' }) {
+		if _, err := path.Match(element, ""); err != nil {
+			return fmt.Errorf("pattern %q: %w", r.pattern, err)
+		}
+	}
+	return nil
+}
+
+func (r ownerRule) match(record ErrorRecord) bool {
+	if r.metadata {
+		pattern := strings.Split(r.pattern, ".")
+		module := strings.Split(record.ErrorModule, ".")
+		if len(pattern) > len(module) {
+			return false
+		}
+		for i, element := range pattern {
+			if matched, _ := path.Match(element, module[i]); !matched {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Without the path, e.g. without Options.SourceDir, the patterns
+	// without folders like "*" match the module name.
+	if record.Path == "" {
+		if strings.Contains(r.pattern, "/") {
+			return false
+		}
+		matched, _ := path.Match(r.pattern, record.ErrorModule)
+		return matched
+	}
+	pattern := r.pattern
+	if !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		pattern = "**/" + pattern
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	return discovery.Match(pattern, record.Path) || discovery.Match(pattern+"/**", record.Path)
+}
@@ -0,0 +1,81 @@
+package converter
+
+import "strings"
+
+// ProjectOptions apply to the records of one project, the Project column
+// holds the configuration or extension name, see Options.Projects.
+type ProjectOptions struct {
+	// The skip lists and the filters are applied in addition to Options.
+	SkipObjects                []string
+	SkipCategories             []string
+	SkipSignificanceCategories []string
+	SkipErrorText              []string
+	Filters                    []Filter
+
+	// Sinks and Output replace the ones passed to Process, GroupBy
+	// replaces Options.GroupBy when set.
+	Sinks   []Sink
+	Output  Output
+	GroupBy string
+}
+
+func (p ProjectOptions) filters() []Filter {
+	return Options{
+		SkipObjects:                p.SkipObjects,
+		SkipCategories:             p.SkipCategories,
+		SkipSignificanceCategories: p.SkipSignificanceCategories,
+		SkipErrorText:              p.SkipErrorText,
+		Filters:                    p.Filters,
+	}.filters()
+}
+
+// recordGroup are the records with the same key in the order of the input.
+type recordGroup struct {
+	key     string
+	records []ErrorRecord
+}
+
+// groupRecords keeps the order in which the keys appear, a record with
+// several keys is a part of every group.
+func groupRecords(records []ErrorRecord, keys func(ErrorRecord) []string) []recordGroup {
+	var groups []recordGroup
+	index := make(map[string]int)
+	for _, record := range records {
+		for _, key := range keys(record) {
+			i, found := index[key]
+			if !found {
+				i = len(groups)
+				index[key] = i
+				groups = append(groups, recordGroup{key: key})
+			}
+			groups[i].records = append(groups[i].records, record)
+		}
+	}
+	return groups
+}
+
+// groupByProject keeps the order in which the projects appear.
+func groupByProject(records []ErrorRecord) []recordGroup {
+	return groupRecords(records, func(record ErrorRecord) []string {
+		return []string{record.Project}
+	})
+}
+
+// fileNamePart replaces the characters which cannot be a part of a file
+// name, e.g. in the team "@org/team".
+func fileNamePart(value string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, value)
+}
+
+// projectName is the report name of the project or group records.
+func projectName(name string, project string) string {
+	if project == "" {
+		return name
+	}
+	return name + "_" + project
+}
@@ -0,0 +1,225 @@
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrorRecord is a single line of the EDT validation results file.
+type ErrorRecord struct {
+	Date        string `xml:"date,attr"`
+	Priority    string `xml:"priority,attr"`
+	CheckType   string `xml:"checkType,attr"`
+	Project     string `xml:"project,attr"`
+	Standard    string `xml:"standard,attr"`
+	ErrorModule string `xml:"errorModule,attr"`
+	ErrorLine   string `xml:"errorLine,attr"`
+	ErrorText   string `xml:"errorText,attr"`
+
+	// Line and Column are parsed from ErrorLine, zero when absent.
+	Line   int `xml:"line,attr"`
+	Column int `xml:"column,attr"`
+
+	// Source names the input of the record, it is not a part of the tsv file.
+	Source string `xml:"source,attr"`
+	// Path is the module file relative to Options.SourceDir, see ResolveModule.
+	Path string `xml:"path,attr"`
+
+	// The last change of the line, see Options.Blame.
+	Commit     string `xml:"commit,attr"`
+	Author     string `xml:"author,attr"`
+	AuthorMail string `xml:"authorMail,attr"`
+
+	// Owner lists the teams owning the module separated by spaces,
+	// see Options.Owners.
+	Owner string `xml:"owner,attr"`
+	// Severity is mapped from Priority, see Options.Severities.
+	Severity string `xml:"severity,attr"`
+}
+
+// NewErrorRecord maps the tsv columns to the record fields.
+// Missing columns are left empty.
+func NewErrorRecord(fields []string) ErrorRecord {
+	field := func(i int) string {
+		if i < len(fields) {
+			return fields[i]
+		}
+		return ""
+	}
+	line, column := ParseLine(field(6))
+	return ErrorRecord{
+		Date:        field(0),
+		Priority:    field(1),
+		CheckType:   field(2),
+		Project:     field(3),
+		Standard:    field(4),
+		ErrorModule: field(5),
+		ErrorLine:   field(6),
+		ErrorText:   field(7),
+		Line:        line,
+		Column:      column,
+	}
+}
+
+// Fingerprint identifies the error without its location column, so that
+// it stays the same when the lines above the error change.
+func (r ErrorRecord) Fingerprint() string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(r.locationFreeKey())))
+}
+
+// linePattern matches the location column of the Russian and English EDT,
+// e.g. "строка 1036", "line 12, column 5".
+var linePattern = regexp.MustCompile(`(?i)^\s*(?:(?:строка|line)\s*)?(\d+)(?:\s*[,;:]?\s*(?:столбец|колонка|column|col\.?)\s*(\d+))?`)
+
+// ParseLine returns the line and the column of the location column,
+// zero values stand for the parts which are absent.
+func ParseLine(errorLine string) (int, int) {
+	match := linePattern.FindStringSubmatch(errorLine)
+	if match == nil {
+		return 0, 0
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	column, _ := strconv.Atoi(match[2])
+	return line, column
+}
+
+// Key identifies the record in the skip errors file.
+// The date and the check type are not a part of the key.
+func (r ErrorRecord) Key() string {
+	return strings.Join([]string{r.Priority, r.Project, r.Standard, r.ErrorModule, r.ErrorLine, r.ErrorText}, "\t")
+}
+
+// locationFreeKey is Key without the location column.
+func (r ErrorRecord) locationFreeKey() string {
+	return strings.Join([]string{r.Priority, r.Project, r.Standard, r.ErrorModule, r.ErrorText}, "\t")
+}
+
+// RecordField returns the record field by its name in the configuration.
+func RecordField(record ErrorRecord, name string) (string, error) {
+	switch name {
+	case "date":
+		return record.Date, nil
+	case "priority":
+		return record.Priority, nil
+	case "check_type":
+		return record.CheckType, nil
+	case "project":
+		return record.Project, nil
+	case "standard":
+		return record.Standard, nil
+	case "error_module":
+		return record.ErrorModule, nil
+	case "error_line":
+		return record.ErrorLine, nil
+	case "error_text":
+		return record.ErrorText, nil
+	case "path":
+		return record.Path, nil
+	case "commit":
+		return record.Commit, nil
+	case "author":
+		return record.Author, nil
+	case "author_mail":
+		return record.AuthorMail, nil
+	case "owner":
+		return record.Owner, nil
+	case "severity":
+		return record.Severity, nil
+	}
+	return "", fmt.Errorf("unknown record field %q", name)
+}
+
+// ReadTSV reads EDT validation results in tsv format,
+// the encoding is detected, see DetectEncoding.
+func ReadTSV(r io.Reader) ([]ErrorRecord, error) {
+	return readTSV(r, EncodingAuto)
+}
+
+func readTSV(r io.Reader, encoding string) ([]ErrorRecord, error) {
+	r, err := decodeReader(r, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = '\t'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]ErrorRecord, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, NewErrorRecord(row))
+	}
+	return records, nil
+}
+
+// BaselineKeys returns keys of the records to be used as Options.Baseline.
+func BaselineKeys(records []ErrorRecord) map[string]struct{} {
+	keys := make(map[string]struct{})
+	for _, record := range records {
+		keys[record.Key()] = struct{}{}
+	}
+	return keys
+}
+
+func recordInSkipErrorsList(record ErrorRecord, parentErrorsKeys map[string]struct{}) bool {
+	if _, found := parentErrorsKeys[record.Key()]; !found {
+		return false
+	}
+	return true
+}
+
+func recordInSkipList(record ErrorRecord, skipObjects []string, skipCategories []string, skipSignificanceCategories []string, skipErrorText []string) bool {
+	return recordInSkipObject(record, skipObjects) || recordInSkipCategory(record, skipCategories) || recordInSkipSignificanteCategories(record, skipSignificanceCategories) || recordInSkipErrorText(record, skipErrorText)
+}
+
+func recordInSkipCategory(record ErrorRecord, skipCategories []string) bool {
+	for _, skip := range skipCategories {
+		if skip == record.CheckType {
+			return true
+		}
+	}
+	return false
+}
+
+func recordInSkipObject(record ErrorRecord, skipObjects []string) bool {
+	for _, skip := range skipObjects {
+		if skip == record.ErrorModule {
+			return true
+		}
+
+		if strings.Contains(record.ErrorModule, skip) {
+			return true
+		}
+	}
+	return false
+}
+
+func recordInSkipSignificanteCategories(record ErrorRecord, skipSignificanceCategories []string) bool {
+	for _, skip := range skipSignificanceCategories {
+		if skip == fmt.Sprintf("%s_%s", record.Priority, record.CheckType) {
+			return true
+		}
+	}
+	return false
+}
+
+func recordInSkipErrorText(record ErrorRecord, skipErrorText []string) bool {
+	for _, skip := range skipErrorText {
+		if skip == record.ErrorText {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// isXMLChar reports whether the rune is allowed in XML 1.0 and is not
+// a control code. Tab, line feed and carriage return are kept.
+func isXMLChar(r rune) bool {
+	switch {
+	case r == '\t' || r == '\n' || r == '\r':
+		return true
+	case r < 0x20 || (r >= 0x7F && r <= 0x9F):
+		return false
+	case r <= 0xD7FF:
+		return true
+	case r >= 0xE000 && r <= 0xFFFD:
+		return true
+	case r >= 0x10000 && r <= 0x10FFFF:
+		return true
+	}
+	return false
+}
+
+// sanitizeText replaces the characters which are not allowed in XML,
+// control codes and invalid UTF-8 bytes, and returns the number of replacements.
+func sanitizeText(s string, replacement string) (string, int) {
+	var b strings.Builder
+	count := 0
+	for i, r := range s {
+		_, width := utf8.DecodeRuneInString(s[i:])
+		if isXMLChar(r) && (r != utf8.RuneError || width > 1) {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteString(replacement)
+		count++
+	}
+
+	if count == 0 {
+		return s, 0
+	}
+	return b.String(), count
+}
+
+// truncateText cuts the text to maxLength characters including the note
+// on the cut characters, zero means no limit.
+func truncateText(s string, maxLength int) string {
+	if maxLength <= 0 || utf8.RuneCountInString(s) <= maxLength {
+		return s
+	}
+
+	// The note gets longer with the number of the cut characters,
+	// so the kept part only shrinks until it fits.
+	runes := []rune(s)
+	keep := maxLength
+	for {
+		note := fmt.Sprintf("… (%d characters truncated)", len(runes)-keep)
+		fit := maxLength - utf8.RuneCountInString(note)
+		if fit < 0 {
+			return string(runes[:maxLength])
+		}
+		if fit == keep {
+			return string(runes[:keep]) + note
+		}
+		keep = fit
+	}
+}
+
+// sanitizeTestSuites cleans every text written to the xml, cuts the failure
+// bodies and returns the number of replaced characters.
+func sanitizeTestSuites(testSuites *TestSuites, replacement string, maxFailureLength int) int {
+	count := 0
+	clean := func(s *string) {
+		var n int
+		*s, n = sanitizeText(*s, replacement)
+		count += n
+	}
+
+	for i := range testSuites.TestSuite {
+		ts := &testSuites.TestSuite[i]
+		clean(&ts.Name)
+		for j := range ts.Properties {
+			clean(&ts.Properties[j].Name)
+			clean(&ts.Properties[j].Value)
+		}
+		for j := range ts.TestCases {
+			tc := &ts.TestCases[j]
+			clean(&tc.ClassName)
+			clean(&tc.Name)
+			clean(&tc.File)
+			if tc.Properties != nil {
+				for k := range tc.Properties.Property {
+					clean(&tc.Properties.Property[k].Name)
+					clean(&tc.Properties.Property[k].Value)
+				}
+			}
+			for k := range tc.Failures {
+				f := &tc.Failures[k]
+				clean(&f.Message)
+				clean(&f.Type)
+				clean(&f.Text)
+				f.Text = truncateText(f.Text, maxFailureLength)
+			}
+		}
+	}
+	return count
+}
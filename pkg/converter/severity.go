@@ -0,0 +1,48 @@
+package converter
+
+import "fmt"
+
+// Severities of the records in the formats which have them.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// DefaultSeverities map the significances of EDT to the severities,
+// the significances which are absent are warnings.
+var DefaultSeverities = map[string]string{
+	"Ошибка конфигурации": SeverityError,
+	"Блокирующая":         SeverityError,
+	"Критическая":         SeverityError,
+	"Значительная":        SeverityWarning,
+	"Незначительная":      SeverityInfo,
+	"Тривиальная":         SeverityInfo,
+	"Configuration error": SeverityError,
+	"Blocker":             SeverityError,
+	"Critical":            SeverityError,
+	"Major":               SeverityWarning,
+	"Minor":               SeverityInfo,
+	"Trivial":             SeverityInfo,
+}
+
+// severity returns the severity of the significance, Options.Severities
+// take precedence over DefaultSeverities.
+func (o Options) severity(significance string) string {
+	if severity, found := o.Severities[significance]; found {
+		return severity
+	}
+	if severity, found := DefaultSeverities[significance]; found {
+		return severity
+	}
+	return SeverityWarning
+}
+
+// CheckSeverity reports an error for a severity which is not known.
+func CheckSeverity(severity string) error {
+	switch severity {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return nil
+	}
+	return fmt.Errorf("unknown severity %q, available: %s, %s, %s", severity, SeverityError, SeverityWarning, SeverityInfo)
+}
@@ -0,0 +1,129 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Report holds the records of one input which passed the filters.
+type Report struct {
+	// Name is the input name without extension, sinks derive file names from it.
+	Name      string
+	Timestamp string
+	Records   []ErrorRecord
+	// Skipped are the records skipped by the filters.
+	Skipped []SkippedRecord
+
+	// SourceDir is the folder the record paths are relative to.
+	SourceDir string
+}
+
+// SkippedRecord is a record with the name of the filter which skipped it,
+// e.g. BaselineFilterName.
+type SkippedRecord struct {
+	Record ErrorRecord
+	Filter string
+}
+
+// Sink writes a report in its format.
+type Sink interface {
+	Write(ctx context.Context, out Output, report Report) error
+}
+
+// SinkFactory creates a sink from its configuration options.
+type SinkFactory func(options map[string]any) (Sink, error)
+
+var sinks = map[string]SinkFactory{}
+
+// RegisterSink makes a sink available by name.
+// It is meant to be called from init functions and panics on duplicate names.
+func RegisterSink(name string, factory SinkFactory) {
+	if _, found := sinks[name]; found {
+		panic("converter: sink registered twice: " + name)
+	}
+	sinks[name] = factory
+}
+
+// NewSink creates a registered sink.
+func NewSink(name string, options map[string]any) (Sink, error) {
+	factory, found := sinks[name]
+	if !found {
+		return nil, fmt.Errorf("unknown sink %q, available: %v", name, Sinks())
+	}
+	return factory(options)
+}
+
+// Sinks returns the names of the registered sinks.
+func Sinks() []string {
+	return registryNames(sinks)
+}
+
+// BaselineSinks returns the sinks which may write the report of the known
+// errors, e.g. of the skip errors file. The annotations, the TeamCity
+// messages and the Allure results would show the known errors as new
+// failures. An empty result of non-nil sinks is not nil.
+func BaselineSinks(sinks []Sink) []Sink {
+	if sinks == nil {
+		return nil
+	}
+	result := []Sink{}
+	for _, sink := range sinks {
+		switch sink.(type) {
+		case GitHubSink, AzureSink, TeamCitySink, AllureSink:
+			continue
+		}
+		result = append(result, sink)
+	}
+	return result
+}
+
+// Output creates the files written by sinks.
+type Output interface {
+	Create(name string) (io.WriteCloser, error)
+}
+
+// DirOutput creates files in the folder. Slash separated names create
+// the subfolders.
+type DirOutput string
+
+func (d DirOutput) Create(name string) (io.WriteCloser, error) {
+	filePath := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0777); err != nil {
+		return nil, err
+	}
+	return os.Create(filePath)
+}
+
+// WriterOutput writes every file to the same writer.
+type WriterOutput struct {
+	W io.Writer
+}
+
+func (o WriterOutput) Create(name string) (io.WriteCloser, error) {
+	return nopCloser{o.W}, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// writeFile creates the file in out and passes it to write.
+func writeFile(out Output, name string, write func(w io.Writer) error) error {
+	file, err := out.Create(name)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", name, err)
+	}
+
+	if err := write(file); err != nil {
+		file.Close()
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return file.Close()
+}
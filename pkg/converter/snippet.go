@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultContextLines is the number of lines shown around the reported line.
+const DefaultContextLines = 2
+
+// sourceFiles reads the module files once per report.
+type sourceFiles struct {
+	dir   string
+	files map[string][]string
+}
+
+func newSourceFiles(dir string) *sourceFiles {
+	return &sourceFiles{dir: dir, files: make(map[string][]string)}
+}
+
+// snippet returns the lines of the file around the line, the line is marked
+// with ">". It returns an empty string when the file cannot be read or
+// the line is out of the file.
+func (f *sourceFiles) snippet(path string, line int, context int) string {
+	lines, found := f.files[path]
+	if !found {
+		data, err := os.ReadFile(filepath.Join(f.dir, filepath.FromSlash(path)))
+		if err == nil {
+			var text string
+			if text, err = DecodeText(data, EncodingAuto); err == nil {
+				lines = strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+			}
+		}
+		f.files[path] = lines
+	}
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	first := max(line-context, 1)
+	last := min(line+context, len(lines))
+	width := len(fmt.Sprint(last))
+
+	var b strings.Builder
+	for i := first; i <= last; i++ {
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s %*d | %s\n", marker, width, i, lines[i-1])
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,77 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Source reads error records from validation results.
+type Source interface {
+	Read(ctx context.Context, r io.Reader) ([]ErrorRecord, error)
+}
+
+// SourceFactory creates a source from its configuration options.
+type SourceFactory func(options map[string]any) (Source, error)
+
+var sources = map[string]SourceFactory{}
+
+// RegisterSource makes a source available by name.
+// It is meant to be called from init functions and panics on duplicate names.
+func RegisterSource(name string, factory SourceFactory) {
+	if _, found := sources[name]; found {
+		panic("converter: source registered twice: " + name)
+	}
+	sources[name] = factory
+}
+
+// NewSource creates a registered source.
+func NewSource(name string, options map[string]any) (Source, error) {
+	factory, found := sources[name]
+	if !found {
+		return nil, fmt.Errorf("unknown source %q, available: %v", name, Sources())
+	}
+	return factory(options)
+}
+
+// Sources returns the names of the registered sources.
+func Sources() []string {
+	return registryNames(sources)
+}
+
+func registryNames[T any](registry map[string]T) []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TSVSource reads the tsv file written by EDT validation.
+type TSVSource struct {
+	// Encoding of the file, empty means EncodingAuto.
+	Encoding string
+}
+
+func (s TSVSource) Read(ctx context.Context, r io.Reader) ([]ErrorRecord, error) {
+	return readTSV(r, s.Encoding)
+}
+
+type tsvOptions struct {
+	Encoding string `json:"encoding"`
+}
+
+func init() {
+	RegisterSource("edt_tsv", func(options map[string]any) (Source, error) {
+		var opts tsvOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		if _, err := DecodeText(nil, opts.Encoding); err != nil {
+			return nil, err
+		}
+		return TSVSource{Encoding: opts.Encoding}, nil
+	})
+}
@@ -0,0 +1,61 @@
+package converter
+
+import (
+	"sort"
+	"strings"
+)
+
+// Count is the number of the records with the same value.
+type Count struct {
+	Name  string
+	Count int
+}
+
+// countBy counts the items by the key, the most frequent first.
+func countBy[T any](items []T, key func(T) string) []Count {
+	index := make(map[string]int)
+	var counts []Count
+	for _, item := range items {
+		name := key(item)
+		i, found := index[name]
+		if !found {
+			i = len(counts)
+			index[name] = i
+			counts = append(counts, Count{Name: name})
+		}
+		counts[i].Count++
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// countSkipped counts the skipped records by the filter.
+func countSkipped(skipped []SkippedRecord) []Count {
+	return countBy(skipped, func(record SkippedRecord) string {
+		return record.Filter
+	})
+}
+
+// topObject returns the top-level metadata object of the module,
+// e.g. "Справочник.Валюты" of "Справочник.Валюты.Форма.Элемент.Форма.Модуль".
+func topObject(module string) string {
+	parts := strings.SplitN(module, ".", 3)
+	if len(parts) < 2 {
+		return module
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// first returns at most n counts, all of them when n is not positive.
+func first(counts []Count, n int) []Count {
+	if n > 0 && len(counts) > n {
+		return counts[:n]
+	}
+	return counts
+}
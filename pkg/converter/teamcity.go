@@ -0,0 +1,121 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// TeamCitySink prints the test suites and the inspections as TeamCity
+// service messages, so the records are shown in the Tests and
+// the Inspections tabs of the build.
+type TeamCitySink struct {
+	// PathPrefix is the path of Options.SourceDir in the repository, e.g. "src/".
+	PathPrefix string
+	// W receives the messages, nil means os.Stdout.
+	W io.Writer
+
+	// Logger receives debug messages, nil means slog.Default.
+	Logger *slog.Logger
+}
+
+var teamCityEscaper = strings.NewReplacer(
+	"|", "||", "'", "|'", "\n", "|n", "\r", "|r", "[", "|[", "]", "|]",
+	"\u0085", "|x", "\u2028", "|l", "\u2029", "|p",
+)
+
+// teamCityMessage formats the service message, the attributes are
+// the name and value pairs.
+func teamCityMessage(name string, attributes ...string) string {
+	var b strings.Builder
+	b.WriteString("##teamcity[" + name)
+	for i := 0; i+1 < len(attributes); i += 2 {
+		fmt.Fprintf(&b, " %s='%s'", attributes[i], teamCityEscaper.Replace(attributes[i+1]))
+	}
+	b.WriteString("]\n")
+	return b.String()
+}
+
+func (s TeamCitySink) Write(ctx context.Context, out Output, report Report) error {
+	var b strings.Builder
+	for _, ts := range NewTestSuites(report, loggerOrDefault(s.Logger)).TestSuite {
+		b.WriteString(teamCityMessage("testSuiteStarted", "name", ts.Name))
+		names := make(map[string]int)
+		for _, tc := range ts.TestCases {
+			name := teamCityTestName(tc)
+			names[name]++
+			if n := names[name]; n > 1 {
+				name = fmt.Sprintf("%s (%d)", name, n)
+			}
+			b.WriteString(teamCityMessage("testStarted", "name", name))
+			for _, f := range tc.Failures {
+				b.WriteString(teamCityMessage("testFailed", "name", name, "message", f.Message, "details", f.Text))
+			}
+			b.WriteString(teamCityMessage("testFinished", "name", name))
+		}
+		b.WriteString(teamCityMessage("testSuiteFinished", "name", ts.Name))
+	}
+
+	types := make(map[string]struct{})
+	for _, record := range report.Records {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		typeID := inspectionTypeID(record)
+		if _, found := types[typeID]; !found {
+			types[typeID] = struct{}{}
+			category := record.CheckType
+			if category == "" {
+				category = record.Priority
+			}
+			b.WriteString(teamCityMessage("inspectionType", "id", typeID, "name", typeID, "description", category, "category", category))
+		}
+
+		file := record.ErrorModule
+		if record.Path != "" {
+			file = s.PathPrefix + record.Path
+		}
+		attributes := []string{"typeId", typeID, "message", record.ErrorText, "file", file}
+		if record.Line > 0 {
+			attributes = append(attributes, "line", fmt.Sprint(record.Line))
+		}
+		attributes = append(attributes, "SEVERITY", strings.ToUpper(record.Severity))
+		b.WriteString(teamCityMessage("inspection", attributes...))
+	}
+
+	_, err := io.WriteString(writerOrStdout(s.W), b.String())
+	return err
+}
+
+// teamCityTestName is the module and the line of the test case, so that
+// the name and the test history do not depend on the other records.
+func teamCityTestName(tc TestCase) string {
+	if tc.Line > 0 {
+		return fmt.Sprintf("%s:%d", tc.Name, tc.Line)
+	}
+	return tc.Name
+}
+
+// inspectionTypeID is the check of the record: the standard, the category
+// or the significance, whichever is present.
+func inspectionTypeID(record ErrorRecord) string {
+	for _, id := range []string{record.Standard, record.CheckType, record.Priority} {
+		if id != "" {
+			return id
+		}
+	}
+	return "edt"
+}
+
+func init() {
+	RegisterSink("teamcity", func(options map[string]any) (Sink, error) {
+		var opts annotationOptions
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return TeamCitySink{PathPrefix: opts.PathPrefix}, nil
+	})
+}
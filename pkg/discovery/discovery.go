@@ -0,0 +1,84 @@
+// Package discovery finds the input files by glob patterns.
+package discovery
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Find walks the root folder and returns the slash separated paths of the files,
+// relative to root, which match any of the include patterns and none of the
+// exclude patterns. The paths are in lexical order.
+func Find(root string, include []string, exclude []string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if entry.IsDir() {
+			if rel != "." && MatchAny(exclude, rel+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if MatchAny(include, rel) && !MatchAny(exclude, rel) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// MatchAny reports whether the path matches any of the patterns.
+func MatchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if Match(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether the slash separated path matches the pattern.
+// The pattern has the path.Match syntax, and a "**" element matches
+// any number of folders. A path ending with "/" is a folder, it matches
+// the patterns which match everything inside it, like "archive/**".
+func Match(pattern string, name string) bool {
+	if strings.HasSuffix(name, "/") {
+		return strings.HasSuffix(pattern, "/**") && Match(strings.TrimSuffix(pattern, "/**"), strings.TrimSuffix(name, "/"))
+	}
+	return matchElements(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchElements(pattern []string, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchElements(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		matched, err := path.Match(pattern[0], name[0])
+		if err != nil || !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,52 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		output  bool
+	}{
+		{"*.tsv", "a.tsv", true},
+		{"*.tsv", "sub/a.tsv", false},
+		{"**/*.tsv", "a.tsv", true},
+		{"**/*.tsv", "sub/deep/a.tsv", true},
+		{"**/validation-*.tsv", "cf/validation-cf.tsv", true},
+		{"**/validation-*.tsv", "cf/other.tsv", false},
+		{"archive/**", "archive/2024/a.tsv", true},
+		{"archive/**", "archive/", true},
+		{"archive/**", "cf/archive/", false},
+		{"**/archive/**", "cf/archive/", true},
+		{"[", "a", false},
+	}
+
+	for _, tt := range tests {
+		result := Match(tt.pattern, tt.name)
+		if result != tt.output {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, result, tt.output)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.tsv", "b.txt", "cf/validation-cf.tsv", "cf/other.tsv", "archive/validation-old.tsv", "ext/x/validation-ext.tsv"} {
+		filePath := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(filePath), 0777)
+		if err := os.WriteFile(filePath, nil, 0666); err != nil {
+			t.Fatalf("failed creating file: %v", err)
+		}
+	}
+
+	files, err := Find(root, []string{"*.tsv", "**/validation-*.tsv"}, []string{"archive/**"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.tsv", "cf/validation-cf.tsv", "ext/x/validation-ext.tsv"}, files)
+}
@@ -1,6 +1,7 @@
 package logging
 
 import (
+	"io"
 	"log/slog"
 	"os"
 )
@@ -15,9 +16,18 @@ func createLoggerFile(logFilePath string) *os.File {
 }
 
 func CreateLogger(logFilePath string, debugFlag *bool) *slog.Logger {
+	return createWriterLogger(createLoggerFile(logFilePath), debugFlag)
+}
+
+// CreateStderrLogger is used when the reports are written to stdout.
+func CreateStderrLogger(debugFlag *bool) *slog.Logger {
+	return createWriterLogger(os.Stderr, debugFlag)
+}
+
+func createWriterLogger(w io.Writer, debugFlag *bool) *slog.Logger {
 	var programLevel = new(slog.LevelVar)
 	//stdoutHandler := slog.NewJSONHandler(os.Stdout, nil)
-	fileHandler := slog.NewJSONHandler(createLoggerFile(logFilePath), &slog.HandlerOptions{Level: programLevel})
+	fileHandler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: programLevel})
 	logger := slog.New(fileHandler)
 	slog.SetDefault(logger)
 